@@ -0,0 +1,156 @@
+package pbixrewriter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// dataMashup is the binary DataMashup part of a PBIX, which holds the Power Query M formulas of the queries.
+// The package parts are a zip containing the formulas, the remaining sections are kept as is
+type dataMashup struct {
+	version            uint32
+	packageParts       []byte
+	permissions        []byte
+	metadata           []byte
+	permissionBindings []byte
+}
+
+func readDataMashup(data []byte) (*dataMashup, error) {
+	reader := bytes.NewReader(data)
+
+	mashup := &dataMashup{}
+	if err := binary.Read(reader, binary.LittleEndian, &mashup.version); err != nil {
+		return nil, fmt.Errorf("DataMashup is not valid: %s", err)
+	}
+	for _, section := range []*[]byte{&mashup.packageParts, &mashup.permissions, &mashup.metadata, &mashup.permissionBindings} {
+		var length uint32
+		if err := binary.Read(reader, binary.LittleEndian, &length); err != nil {
+			return nil, fmt.Errorf("DataMashup is not valid: %s", err)
+		}
+		if int64(length) > int64(reader.Len()) {
+			return nil, fmt.Errorf("DataMashup is not valid: section length %d exceeds the remaining %d bytes", length, reader.Len())
+		}
+		*section = make([]byte, length)
+		if _, err := io.ReadFull(reader, *section); err != nil {
+			return nil, err
+		}
+	}
+	return mashup, nil
+}
+
+func (mashup *dataMashup) bytes() []byte {
+	var output bytes.Buffer
+	binary.Write(&output, binary.LittleEndian, mashup.version)
+	for _, section := range [][]byte{mashup.packageParts, mashup.permissions, mashup.metadata, mashup.permissionBindings} {
+		binary.Write(&output, binary.LittleEndian, uint32(len(section)))
+		output.Write(section)
+	}
+	return output.Bytes()
+}
+
+func (mashup *dataMashup) packagePartsReader() (*zip.Reader, error) {
+	reader, err := zip.NewReader(bytes.NewReader(mashup.packageParts), int64(len(mashup.packageParts)))
+	if err != nil {
+		return nil, fmt.Errorf("DataMashup package parts are not valid: %s", err)
+	}
+	return reader, nil
+}
+
+func isFormulaPart(file *zip.File) bool {
+	return strings.HasPrefix(file.Name, "Formulas/") && strings.HasSuffix(file.Name, ".m")
+}
+
+// rewriteDataMashupFormulas applies the rewrite to each of the formulas within the DataMashup
+func rewriteDataMashupFormulas(data []byte, rewrite func(formula string) (string, error)) ([]byte, error) {
+	mashup, err := readDataMashup(data)
+	if err != nil {
+		return nil, err
+	}
+
+	packagePartsReader, err := mashup.packagePartsReader()
+	if err != nil {
+		return nil, err
+	}
+
+	// the package parts are a zip just like the PBIX, so are rewritten the same way
+	var packageParts bytes.Buffer
+	packagePartsWriter := zip.NewWriter(&packageParts)
+	err = RewritePbix(packagePartsReader, packagePartsWriter, []PipelineFunc{
+		func(file *zip.File, reader io.Reader, next PipelineFuncNext) error {
+			if !isFormulaPart(file) {
+				return next(file, reader)
+			}
+
+			formula, err := ioutil.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			rewrittenFormula, err := rewrite(string(formula))
+			if err != nil {
+				return err
+			}
+			return next(file, strings.NewReader(rewrittenFormula))
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := packagePartsWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	mashup.packageParts = packageParts.Bytes()
+	return mashup.bytes(), nil
+}
+
+// ReadDataMashupFormulas returns the Power Query M formulas within the DataMashup of a PBIX, keyed by their part name
+func ReadDataMashupFormulas(input *zip.Reader) (map[string]string, error) {
+	for _, file := range input.File {
+		if file.Name != "DataMashup" {
+			continue
+		}
+
+		reader, err := file.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+
+		data, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		mashup, err := readDataMashup(data)
+		if err != nil {
+			return nil, err
+		}
+		packagePartsReader, err := mashup.packagePartsReader()
+		if err != nil {
+			return nil, err
+		}
+
+		formulas := make(map[string]string)
+		for _, part := range packagePartsReader.File {
+			if !isFormulaPart(part) {
+				continue
+			}
+			partReader, err := part.Open()
+			if err != nil {
+				return nil, err
+			}
+			formula, err := ioutil.ReadAll(partReader)
+			partReader.Close()
+			if err != nil {
+				return nil, err
+			}
+			formulas[part.Name] = string(formula)
+		}
+		return formulas, nil
+	}
+	return nil, fmt.Errorf("PBIX does not contain a DataMashup")
+}
@@ -2,6 +2,8 @@ package pbixrewriter
 
 import (
 	"archive/zip"
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -35,3 +37,72 @@ func SetDatasetIDPipelineFunc(datasetID string) PipelineFunc {
 		return next(file, reader)
 	}
 }
+
+// ReplaceServerPipelineFunc replaces a server name used by the queries within a PBIX
+func ReplaceServerPipelineFunc(originalServer string, server string) PipelineFunc {
+	return replaceTextLiteralsPipelineFunc("Server", originalServer, server)
+}
+
+// ReplaceDatabasePipelineFunc replaces a database name used by the queries within a PBIX
+func ReplaceDatabasePipelineFunc(originalDatabase string, database string) PipelineFunc {
+	return replaceTextLiteralsPipelineFunc("Database", originalDatabase, database)
+}
+
+// ReplaceURLPipelineFunc replaces a URL used by the queries within a PBIX
+func ReplaceURLPipelineFunc(originalURL string, url string) PipelineFunc {
+	return replaceTextLiteralsPipelineFunc("URL", originalURL, url)
+}
+
+// SetParameterPipelineFunc sets the value of a parameter within a PBIX, which is the value the parameter has once uploaded
+func SetParameterPipelineFunc(name string, value string) PipelineFunc {
+	return rewriteFormulasPipelineFunc(func(formula string) (string, bool, error) {
+		return setParameterValue(formula, name, value)
+	}, fmt.Sprintf("Parameter %s was not found in the PBIX queries", name))
+}
+
+func replaceTextLiteralsPipelineFunc(description string, original string, replacement string) PipelineFunc {
+	return rewriteFormulasPipelineFunc(func(formula string) (string, bool, error) {
+		formula, replaced := replaceTextLiterals(formula, original, replacement)
+		return formula, replaced > 0, nil
+	}, fmt.Sprintf("%s '%s' was not found in the PBIX queries", description, original))
+}
+
+// rewriteFormulasPipelineFunc rewrites the Power Query M formulas within the DataMashup of a PBIX. The rewrite reports
+// whether it changed a formula, and if no formula in the whole PBIX is changed the rewrite fails with the notFound
+// message, including when the PBIX has no DataMashup. The returned PipelineFunc can only be used for a single rewrite
+func rewriteFormulasPipelineFunc(rewrite func(formula string) (string, bool, error), notFound string) PipelineFunc {
+	found := false
+	return func(file *zip.File, reader io.Reader, next PipelineFuncNext) error {
+		if file == endOfPbix {
+			if !found {
+				return errors.New(notFound)
+			}
+			return next(file, reader)
+		}
+
+		if file.Name == "SecurityBindings" {
+			// security bindings needs to be deleted, otherwise file appears corrupt
+			// if opening file on machine it was previously opened on
+			return nil
+		}
+
+		if file.Name == "DataMashup" {
+			data, err := ioutil.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+
+			data, err = rewriteDataMashupFormulas(data, func(formula string) (string, error) {
+				formula, changed, err := rewrite(formula)
+				found = found || changed
+				return formula, err
+			})
+			if err != nil {
+				return err
+			}
+			return next(file, bytes.NewReader(data))
+		}
+
+		return next(file, reader)
+	}
+}
@@ -0,0 +1,200 @@
+package pbixrewriter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testSection = `section Section1;
+
+shared Sales = let
+    Source = Sql.Database("sql-dev.contoso.local", "Sales")
+in
+    Source;
+
+shared Products = OData.Feed("https://services.odata.org/V3/OData/OData.svc/");
+
+shared Region = "West" meta [IsParameterQuery=true, Type="Text", IsParameterQueryRequired=true];
+`
+
+// buildTestPbix returns a PBIX containing a DataMashup with the given section, along with parts that are not rewritten
+func buildTestPbix(t *testing.T, section string) *zip.Reader {
+	var packageParts bytes.Buffer
+	packagePartsWriter := zip.NewWriter(&packageParts)
+	for name, content := range map[string]string{
+		"[Content_Types].xml": `<?xml version="1.0" encoding="utf-8"?><Types />`,
+		"Config/Package.xml":  `<?xml version="1.0" encoding="utf-8"?><Package />`,
+		"Formulas/Section1.m": section,
+	} {
+		writer, err := packagePartsWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write([]byte(content))
+	}
+	if err := packagePartsWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var dataMashup bytes.Buffer
+	binary.Write(&dataMashup, binary.LittleEndian, uint32(0))
+	for _, section := range [][]byte{packageParts.Bytes(), []byte("<Permissions />"), []byte("metadata"), []byte("bindings")} {
+		binary.Write(&dataMashup, binary.LittleEndian, uint32(len(section)))
+		dataMashup.Write(section)
+	}
+
+	var pbix bytes.Buffer
+	pbixWriter := zip.NewWriter(&pbix)
+	for name, content := range map[string][]byte{
+		"DataMashup":       dataMashup.Bytes(),
+		"SecurityBindings": []byte("bindings"),
+		"Version":          []byte("1.18"),
+	} {
+		writer, err := pbixWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		writer.Write(content)
+	}
+	if err := pbixWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(pbix.Bytes()), int64(pbix.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader
+}
+
+func rewriteTestPbix(t *testing.T, input *zip.Reader, pipelineFuncs []PipelineFunc) (*zip.Reader, error) {
+	var output bytes.Buffer
+	outputWriter := zip.NewWriter(&output)
+	if err := RewritePbix(input, outputWriter, pipelineFuncs); err != nil {
+		return nil, err
+	}
+	if err := outputWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(output.Bytes()), int64(output.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader, nil
+}
+
+func TestRewriteFormulasPipelineFuncs(t *testing.T) {
+	output, err := rewriteTestPbix(t, buildTestPbix(t, testSection), []PipelineFunc{
+		ReplaceServerPipelineFunc("sql-dev.contoso.local", "sql.contoso.local"),
+		ReplaceDatabasePipelineFunc("Sales", "SalesProd"),
+		ReplaceURLPipelineFunc("https://services.odata.org/V3/OData/OData.svc/", "https://services.odata.org/V4/OData/OData.svc/"),
+		SetParameterPipelineFunc("Region", "East"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	formulas, err := ReadDataMashupFormulas(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	section := formulas["Formulas/Section1.m"]
+	for _, expected := range []string{
+		`Sql.Database("sql.contoso.local", "SalesProd")`,
+		`OData.Feed("https://services.odata.org/V4/OData/OData.svc/")`,
+		`shared Region = "East" meta`,
+	} {
+		if !strings.Contains(section, expected) {
+			t.Errorf("Expected rewritten formulas to contain %s. Found %s", expected, section)
+		}
+	}
+
+	var names []string
+	for _, file := range output.File {
+		names = append(names, file.Name)
+	}
+	sort.Strings(names)
+	if strings.Join(names, ",") != "DataMashup,Version" {
+		t.Errorf("Expected SecurityBindings to be removed. Found parts %v", names)
+	}
+}
+
+func TestRewriteFormulasPipelineFuncs_preservesOtherSections(t *testing.T) {
+	output, err := rewriteTestPbix(t, buildTestPbix(t, testSection), []PipelineFunc{
+		SetParameterPipelineFunc("Region", "East"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range output.File {
+		if file.Name != "DataMashup" {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var data bytes.Buffer
+		data.ReadFrom(reader)
+		reader.Close()
+
+		mashup, err := readDataMashup(data.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(mashup.permissions) != "<Permissions />" || string(mashup.metadata) != "metadata" || string(mashup.permissionBindings) != "bindings" {
+			t.Errorf("Expected DataMashup sections other than the package parts to be unchanged. Found %q, %q, %q", mashup.permissions, mashup.metadata, mashup.permissionBindings)
+		}
+	}
+}
+
+func TestRewriteFormulasPipelineFuncs_notFound(t *testing.T) {
+	tests := []struct {
+		pipelineFunc  PipelineFunc
+		expectedError string
+	}{
+		{ReplaceServerPipelineFunc("sql-test.contoso.local", "sql.contoso.local"), "Server 'sql-test.contoso.local' was not found in the PBIX queries"},
+		{ReplaceDatabasePipelineFunc("Inventory", "InventoryProd"), "Database 'Inventory' was not found in the PBIX queries"},
+		{ReplaceURLPipelineFunc("https://contoso.com", "https://contoso.net"), "URL 'https://contoso.com' was not found in the PBIX queries"},
+		{SetParameterPipelineFunc("Country", "NZ"), "Parameter Country was not found in the PBIX queries"},
+	}
+
+	for _, test := range tests {
+		_, err := rewriteTestPbix(t, buildTestPbix(t, testSection), []PipelineFunc{test.pipelineFunc})
+		if err == nil || err.Error() != test.expectedError {
+			t.Errorf("Expected error %s. Found %v", test.expectedError, err)
+		}
+	}
+}
+
+func TestRewriteFormulasPipelineFuncs_noDataMashup(t *testing.T) {
+	var pbix bytes.Buffer
+	pbixWriter := zip.NewWriter(&pbix)
+	writer, err := pbixWriter.Create("Version")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer.Write([]byte("1.18"))
+	if err := pbixWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	input, err := zip.NewReader(bytes.NewReader(pbix.Bytes()), int64(pbix.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = rewriteTestPbix(t, input, []PipelineFunc{
+		SetDatasetIDPipelineFunc("00000000-0000-0000-0000-000000000001"),
+		ReplaceServerPipelineFunc("sql-dev.contoso.local", "sql.contoso.local"),
+	})
+	expectedError := "Server 'sql-dev.contoso.local' was not found in the PBIX queries"
+	if err == nil || err.Error() != expectedError {
+		t.Errorf("Expected error %s. Found %v", expectedError, err)
+	}
+}
@@ -0,0 +1,109 @@
+package pbixrewriter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var parameterMetaRegex = regexp.MustCompile(`^\s*meta\s*\[[^\]]*IsParameterQuery\s*=\s*true`)
+
+// replaceTextLiterals replaces the Power Query M text literals equal to original, ignoring case, with replacement.
+// Quoted identifiers and comments are left as is. The number of text literals replaced is returned
+func replaceTextLiterals(formula string, original string, replacement string) (string, int) {
+	var output strings.Builder
+	replaced := 0
+
+	for i := 0; i < len(formula); {
+		var end int
+		switch {
+		case strings.HasPrefix(formula[i:], "//"):
+			end = strings.Index(formula[i:], "\n")
+			if end < 0 {
+				end = len(formula)
+			} else {
+				end += i
+			}
+		case strings.HasPrefix(formula[i:], "/*"):
+			end = strings.Index(formula[i+2:], "*/")
+			if end < 0 {
+				end = len(formula)
+			} else {
+				end += i + 4
+			}
+		case strings.HasPrefix(formula[i:], `#"`):
+			end = textLiteralEnd(formula, i+1)
+		case formula[i] == '"':
+			end = textLiteralEnd(formula, i)
+			if strings.EqualFold(decodeTextLiteral(formula[i:end]), original) {
+				output.WriteString(encodeTextLiteral(replacement))
+				replaced++
+				i = end
+				continue
+			}
+		default:
+			end = i + 1
+		}
+		output.WriteString(formula[i:end])
+		i = end
+	}
+
+	return output.String(), replaced
+}
+
+// setParameterValue sets the current value of the parameter query with the given name. Text parameters are
+// given a text literal, other parameters the value as is so numbers, dates and logicals can be set.
+// Whether the parameter was found is returned
+func setParameterValue(formula string, name string, value string) (string, bool, error) {
+	sharedRegex := regexp.MustCompile(`(?m)^\s*shared\s+(?:` + regexp.QuoteMeta(name) + `|#"` + regexp.QuoteMeta(strings.ReplaceAll(name, `"`, `""`)) + `")\s*=\s*`)
+	location := sharedRegex.FindStringIndex(formula)
+	if location == nil {
+		return formula, false, nil
+	}
+
+	valueStart := location[1]
+	var valueEnd int
+	var newValue string
+	if strings.HasPrefix(formula[valueStart:], `"`) {
+		valueEnd = textLiteralEnd(formula, valueStart)
+		newValue = encodeTextLiteral(value)
+	} else {
+		metaIndex := strings.Index(formula[valueStart:], " meta ")
+		if metaIndex < 0 {
+			return formula, true, fmt.Errorf("Query %s is not a parameter", name)
+		}
+		valueEnd = valueStart + metaIndex
+		newValue = value
+	}
+
+	if !parameterMetaRegex.MatchString(formula[valueEnd:]) {
+		return formula, true, fmt.Errorf("Query %s is not a parameter", name)
+	}
+
+	return formula[:valueStart] + newValue + formula[valueEnd:], true, nil
+}
+
+// textLiteralEnd returns the index after the text literal starting at start, where quotes are escaped by doubling them
+func textLiteralEnd(formula string, start int) int {
+	for i := start + 1; i < len(formula); i++ {
+		if formula[i] != '"' {
+			continue
+		}
+		if i+1 < len(formula) && formula[i+1] == '"' {
+			i++
+			continue
+		}
+		return i + 1
+	}
+	return len(formula)
+}
+
+func decodeTextLiteral(literal string) string {
+	literal = strings.TrimPrefix(literal, `"`)
+	literal = strings.TrimSuffix(literal, `"`)
+	return strings.ReplaceAll(strings.ReplaceAll(literal, `""`, `"`), "#(#)", "#")
+}
+
+func encodeTextLiteral(value string) string {
+	return `"` + strings.ReplaceAll(strings.ReplaceAll(value, "#(", "#(#)("), `"`, `""`) + `"`
+}
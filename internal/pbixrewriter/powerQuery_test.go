@@ -0,0 +1,142 @@
+package pbixrewriter
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplaceTextLiterals(t *testing.T) {
+	tests := []struct {
+		name             string
+		formula          string
+		original         string
+		replacement      string
+		expectedFormula  string
+		expectedReplaced int
+	}{
+		{
+			name:             "function arguments",
+			formula:          `Source = Sql.Database("sql-dev.contoso.local", "Sales")`,
+			original:         "sql-dev.contoso.local",
+			replacement:      "sql.contoso.local",
+			expectedFormula:  `Source = Sql.Database("sql.contoso.local", "Sales")`,
+			expectedReplaced: 1,
+		},
+		{
+			name:             "case is ignored",
+			formula:          `Sql.Database("SQL-DEV", "Sales"), Sql.Database("sql-dev", "Inventory")`,
+			original:         "sql-dev",
+			replacement:      "sql-prod",
+			expectedFormula:  `Sql.Database("sql-prod", "Sales"), Sql.Database("sql-prod", "Inventory")`,
+			expectedReplaced: 2,
+		},
+		{
+			name:             "partial matches are left",
+			formula:          `OData.Feed("https://services.odata.org/V3/OData/OData.svc/Products")`,
+			original:         "https://services.odata.org/V3/OData/OData.svc/",
+			replacement:      "https://services.odata.org/V4/OData/OData.svc/",
+			expectedFormula:  `OData.Feed("https://services.odata.org/V3/OData/OData.svc/Products")`,
+			expectedReplaced: 0,
+		},
+		{
+			name:             "quoted identifiers and comments are left",
+			formula:          "#\"Sales\" = Sql.Database(\"sql\", \"Sales\") // \"Sales\"\n/* \"Sales\" */ in #\"Sales\"",
+			original:         "Sales",
+			replacement:      "Sales Prod",
+			expectedFormula:  "#\"Sales\" = Sql.Database(\"sql\", \"Sales Prod\") // \"Sales\"\n/* \"Sales\" */ in #\"Sales\"",
+			expectedReplaced: 1,
+		},
+		{
+			name:             "escaped quotes",
+			formula:          `Text.From("say ""hello""")`,
+			original:         `say "hello"`,
+			replacement:      `say "goodbye" #(tab)`,
+			expectedFormula:  `Text.From("say ""goodbye"" #(#)(tab)")`,
+			expectedReplaced: 1,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			formula, replaced := replaceTextLiterals(test.formula, test.original, test.replacement)
+			if formula != test.expectedFormula {
+				t.Errorf("Expected formula %s. Found %s", test.expectedFormula, formula)
+			}
+			if replaced != test.expectedReplaced {
+				t.Errorf("Expected %d replacements. Found %d", test.expectedReplaced, replaced)
+			}
+		})
+	}
+}
+
+func TestSetParameterValue(t *testing.T) {
+	formula := `section Section1;
+
+shared ParamOne = "ParamOneValue" meta [IsParameterQuery=true, Type="Text", IsParameterQueryRequired=true];
+
+shared #"Param Two" = 5 meta [IsParameterQuery=true, Type="Number", IsParameterQueryRequired=true];
+
+shared Products = "ParamOne";
+`
+
+	tests := []struct {
+		name            string
+		parameter       string
+		value           string
+		expectedFormula string
+		expectedFound   bool
+		expectedError   string
+	}{
+		{
+			name:            "text parameter",
+			parameter:       "ParamOne",
+			value:           `New "Value"`,
+			expectedFormula: `shared ParamOne = "New ""Value""" meta [IsParameterQuery=true`,
+			expectedFound:   true,
+		},
+		{
+			name:            "quoted identifier parameter",
+			parameter:       "Param Two",
+			value:           "10",
+			expectedFormula: `shared #"Param Two" = 10 meta [IsParameterQuery=true`,
+			expectedFound:   true,
+		},
+		{
+			name:          "missing parameter",
+			parameter:     "Param",
+			value:         "value",
+			expectedFound: false,
+		},
+		{
+			name:          "query that is not a parameter",
+			parameter:     "Products",
+			value:         "value",
+			expectedFound: true,
+			expectedError: "Query Products is not a parameter",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rewritten, found, err := setParameterValue(formula, test.parameter, test.value)
+			if test.expectedError != "" {
+				if err == nil || err.Error() != test.expectedError {
+					t.Fatalf("Expected error %s. Found %v", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found != test.expectedFound {
+				t.Errorf("Expected found %t. Found %t", test.expectedFound, found)
+			}
+			if !found && rewritten != formula {
+				t.Errorf("Expected formula to be unchanged. Found %s", rewritten)
+			}
+			if found && !strings.Contains(rewritten, test.expectedFormula) {
+				t.Errorf("Expected formula to contain %s. Found %s", test.expectedFormula, rewritten)
+			}
+		})
+	}
+}
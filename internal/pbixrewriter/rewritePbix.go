@@ -2,6 +2,7 @@ package pbixrewriter
 
 import (
 	"archive/zip"
+	"bytes"
 	"io"
 	"os"
 )
@@ -12,6 +13,10 @@ type PipelineFunc func(file *zip.File, reader io.Reader, next PipelineFuncNext)
 // PipelineFuncNext defines the next function inside a PipelineFunc
 type PipelineFuncNext func(file *zip.File, reader io.Reader) error
 
+// endOfPbix is passed through the pipeline once every file has been rewritten, so PipelineFuncs can fail if
+// something they were to rewrite was never found. It is never written to the output
+var endOfPbix = &zip.File{FileHeader: zip.FileHeader{Name: "EndOfPbix"}}
+
 // RewritePbix rewrites a PBIX file applying the given PipelineFuncs
 func RewritePbix(input *zip.Reader, output *zip.Writer, pipelineFuncs []PipelineFunc) error {
 	for _, inputItem := range input.File {
@@ -19,14 +24,18 @@ func RewritePbix(input *zip.Reader, output *zip.Writer, pipelineFuncs []Pipeline
 		if err != nil {
 			return err
 		}
-		defer inputItemReader.Close()
 
 		pipeline := nestPipelineFunc(0, append(pipelineFuncs, buildWriterPipelineFunc(output)))
 
-		pipeline(inputItem, inputItemReader)
-
+		err = pipeline(inputItem, inputItemReader)
+		inputItemReader.Close()
+		if err != nil {
+			return err
+		}
 	}
-	return nil
+
+	pipeline := nestPipelineFunc(0, append(pipelineFuncs, buildWriterPipelineFunc(output)))
+	return pipeline(endOfPbix, bytes.NewReader(nil))
 }
 
 // RewritePbixFiles rewrites a PBIX file applying the given PipelineFuncs
@@ -41,20 +50,34 @@ func RewritePbixFiles(inputPbixFile string, outputPbixFile string, pipelineFuncs
 	if err != nil {
 		return err
 	}
+	defer targetFile.Close()
+
 	targetZipWriter := zip.NewWriter(targetFile)
-	defer targetZipWriter.Close()
+	err = RewritePbix(&zipReader.Reader, targetZipWriter, pipelineFuncs)
+	if err != nil {
+		return err
+	}
 
-	return RewritePbix(&zipReader.Reader, targetZipWriter, pipelineFuncs)
+	err = targetZipWriter.Close()
+	if err != nil {
+		return err
+	}
+	return targetFile.Close()
 }
 
 func buildWriterPipelineFunc(writer *zip.Writer) PipelineFunc {
 
 	return func(file *zip.File, reader io.Reader, next PipelineFuncNext) error {
+		if file == endOfPbix {
+			return nil
+		}
+
 		header, err := zip.FileInfoHeader(file.FileInfo())
 		if err != nil {
 			return err
 		}
 		header.Name = file.Name
+		header.Method = file.Method
 
 		outputItemWriter, err := writer.CreateHeader(header)
 		if err != nil {
@@ -0,0 +1,112 @@
+package powerbi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DataSourceCapacities represents the Power BI capacities the user has access to
+func DataSourceCapacities() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCapacitiesRead,
+
+		Schema: map[string]*schema.Schema{
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return capacities with this display name.",
+			},
+			"sku": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return capacities with this SKU, for example `A1` or `P1`.",
+			},
+			"capacities": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The capacities matching the filters.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the capacity.",
+						},
+						"display_name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The display name of the capacity.",
+						},
+						"sku": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The SKU of the capacity.",
+						},
+						"state": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The state of the capacity.",
+						},
+						"region": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The Azure region the capacity is provisioned in.",
+						},
+						"admins": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "The admins of the capacity.",
+						},
+						"capacity_user_access_right": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The access right the user or service principal has on the capacity.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceCapacitiesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	capacities, err := client.GetCapacities()
+	if err != nil {
+		return err
+	}
+
+	displayName := d.Get("display_name").(string)
+	sku := d.Get("sku").(string)
+
+	results := make([]interface{}, 0)
+	for _, capacity := range capacities.Value {
+		if displayName != "" && capacity.DisplayName != displayName {
+			continue
+		}
+		if sku != "" && !strings.EqualFold(capacity.SKU, sku) {
+			continue
+		}
+		results = append(results, map[string]interface{}{
+			"id":                         capacity.ID,
+			"display_name":               capacity.DisplayName,
+			"sku":                        capacity.SKU,
+			"state":                      capacity.State,
+			"region":                     capacity.Region,
+			"admins":                     convertCapacityAdminsToStringSlice(capacity.Admins),
+			"capacity_user_access_right": capacity.CapacityUserAccessRight,
+		})
+	}
+
+	// data sources need an id, base it on the filters so it is stable between runs
+	d.SetId(strconv.Itoa(hashcode.String(displayName + "|" + sku)))
+	d.Set("capacities", results)
+
+	return nil
+}
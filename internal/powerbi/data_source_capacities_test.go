@@ -0,0 +1,44 @@
+package powerbi
+
+import (
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceCapacities_basic(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.AddCapacity(powerbiapitest.Capacity{DisplayName: "Capacity A", SKU: "A1"})
+	server.AddCapacity(powerbiapitest.Capacity{DisplayName: "Capacity B", SKU: "A1"})
+	server.AddCapacity(powerbiapitest.Capacity{DisplayName: "Capacity C", SKU: "P1"})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "powerbi_capacities" "all" {
+				}
+
+				data "powerbi_capacities" "by_sku" {
+					sku = "a1"
+				}
+
+				data "powerbi_capacities" "by_name" {
+					display_name = "Capacity C"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_capacities.all", "capacities.#", "3"),
+					resource.TestCheckResourceAttr("data.powerbi_capacities.by_sku", "capacities.#", "2"),
+					resource.TestCheckResourceAttr("data.powerbi_capacities.by_sku", "capacities.0.display_name", "Capacity A"),
+					resource.TestCheckResourceAttr("data.powerbi_capacities.by_sku", "capacities.1.display_name", "Capacity B"),
+					resource.TestCheckResourceAttr("data.powerbi_capacities.by_name", "capacities.#", "1"),
+					resource.TestCheckResourceAttr("data.powerbi_capacities.by_name", "capacities.0.sku", "P1"),
+				),
+			},
+		},
+	})
+}
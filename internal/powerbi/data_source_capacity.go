@@ -0,0 +1,107 @@
+package powerbi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DataSourceCapacity represents a single Power BI capacity
+func DataSourceCapacity() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceCapacityRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "display_name"},
+				Description:  "The ID of the capacity. Exactly one of `id` or `display_name` must be provided.",
+			},
+			"display_name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "display_name"},
+				Description:  "The display name of the capacity. Exactly one of `id` or `display_name` must be provided.",
+			},
+			"sku": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The SKU of the capacity, for example `A1` or `P1`.",
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The state of the capacity, for example `Active` or `Suspended`.",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The Azure region the capacity is provisioned in.",
+			},
+			"admins": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The admins of the capacity.",
+			},
+			"capacity_user_access_right": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The access right the user or service principal has on the capacity.",
+			},
+		},
+	}
+}
+
+func dataSourceCapacityRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	capacities, err := client.GetCapacities()
+	if err != nil {
+		return err
+	}
+
+	id := d.Get("id").(string)
+	displayName := d.Get("display_name").(string)
+
+	var matches []powerbiapi.GetCapacitiesResponseItem
+	for _, capacity := range capacities.Value {
+		if (id != "" && strings.EqualFold(capacity.ID, id)) || (displayName != "" && capacity.DisplayName == displayName) {
+			matches = append(matches, capacity)
+		}
+	}
+
+	if len(matches) == 0 {
+		if id != "" {
+			return fmt.Errorf("Capacity id %s not found or logged-in user doesn't have capacity admin rights", id)
+		}
+		return fmt.Errorf("Capacity with display name '%s' not found or logged-in user doesn't have capacity admin rights", displayName)
+	}
+	if len(matches) > 1 {
+		return fmt.Errorf("Found %d capacities with display name '%s'. Use id to select a single capacity", len(matches), displayName)
+	}
+
+	capacity := matches[0]
+	d.SetId(capacity.ID)
+	d.Set("display_name", capacity.DisplayName)
+	d.Set("sku", capacity.SKU)
+	d.Set("state", capacity.State)
+	d.Set("region", capacity.Region)
+	d.Set("admins", convertCapacityAdminsToStringSlice(capacity.Admins))
+	d.Set("capacity_user_access_right", capacity.CapacityUserAccessRight)
+
+	return nil
+}
+
+func convertCapacityAdminsToStringSlice(admins []powerbiapi.CapacityAdmins) []string {
+	result := make([]string, len(admins))
+	for i, admin := range admins {
+		result[i] = string(admin)
+	}
+	return result
+}
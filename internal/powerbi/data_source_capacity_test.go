@@ -0,0 +1,61 @@
+package powerbi
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceCapacity_basic(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	capacity := server.AddCapacity(powerbiapitest.Capacity{
+		DisplayName: "Unit Test Capacity",
+		SKU:         "A1",
+		Region:      "West Europe",
+		Admins:      []string{"admin@example.com"},
+	})
+	server.AddCapacity(powerbiapitest.Capacity{DisplayName: "Other Capacity", SKU: "P1"})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "powerbi_capacity" "by_name" {
+					display_name = "Unit Test Capacity"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_capacity.by_name", "id", capacity.ID),
+					resource.TestCheckResourceAttr("data.powerbi_capacity.by_name", "sku", "A1"),
+					resource.TestCheckResourceAttr("data.powerbi_capacity.by_name", "state", "Active"),
+					resource.TestCheckResourceAttr("data.powerbi_capacity.by_name", "region", "West Europe"),
+					resource.TestCheckResourceAttr("data.powerbi_capacity.by_name", "admins.#", "1"),
+					resource.TestCheckResourceAttr("data.powerbi_capacity.by_name", "admins.0", "admin@example.com"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+				data "powerbi_capacity" "by_id" {
+					id = "%s"
+				}
+				`, capacity.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_capacity.by_id", "display_name", "Unit Test Capacity"),
+				),
+			},
+			{
+				Config: `
+				data "powerbi_capacity" "missing" {
+					display_name = "Missing Capacity"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Capacity with display name 'Missing Capacity' not found"),
+			},
+		},
+	})
+}
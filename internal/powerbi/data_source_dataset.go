@@ -0,0 +1,84 @@
+package powerbi
+
+import (
+	"fmt"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DataSourceDataset represents a single Power BI dataset
+func DataSourceDataset() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDatasetRead,
+
+		Schema: datasetAttributesSchema(map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Workspace ID containing the dataset.",
+			},
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "ID of the dataset. Exactly one of `id` or `name` must be provided.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "Name of the dataset. Exactly one of `id` or `name` must be provided.",
+			},
+		}),
+	}
+}
+
+func dataSourceDatasetRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+
+	var dataset powerbiapi.GetDatasetsInGroupResponseItem
+	if id, ok := d.GetOk("id"); ok {
+		resp, err := client.GetDatasetInGroup(groupID, id.(string))
+		if isHTTP404Error(err) {
+			return fmt.Errorf("Dataset with id %s not found in workspace %s", id, groupID)
+		}
+		if err != nil {
+			return err
+		}
+		dataset = powerbiapi.GetDatasetsInGroupResponseItem(*resp)
+	} else {
+		name := d.Get("name").(string)
+		datasets, err := client.GetDatasetsInGroup(groupID)
+		if err != nil {
+			return err
+		}
+
+		var matches []powerbiapi.GetDatasetsInGroupResponseItem
+		for _, item := range datasets.Value {
+			if item.Name == name {
+				matches = append(matches, item)
+			}
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("Dataset with name '%s' not found in workspace %s", name, groupID)
+		}
+		if len(matches) > 1 {
+			return fmt.Errorf("Found %d datasets with name '%s' in workspace %s. Use id to select a single dataset", len(matches), name, groupID)
+		}
+		dataset = matches[0]
+	}
+
+	d.SetId(dataset.ID)
+	for key, value := range flattenDataset(dataset) {
+		if key != "id" {
+			d.Set(key, value)
+		}
+	}
+
+	return nil
+}
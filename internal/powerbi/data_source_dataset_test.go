@@ -0,0 +1,64 @@
+package powerbi
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceDataset_basic(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = powerbi_workspace.test.id
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config: config + `
+				data "powerbi_dataset" "by_name" {
+					workspace_id = powerbi_workspace.test.id
+					name = "Unit Test PBIX"
+				}
+
+				data "powerbi_dataset" "by_id" {
+					workspace_id = powerbi_workspace.test.id
+					id = powerbi_pbix.test.dataset_id
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.powerbi_dataset.by_name", "id", "powerbi_pbix.test", "dataset_id"),
+					resource.TestCheckResourceAttr("data.powerbi_dataset.by_name", "target_storage_mode", "Abf"),
+					resource.TestCheckResourceAttr("data.powerbi_dataset.by_name", "is_refreshable", "true"),
+					resource.TestCheckResourceAttr("data.powerbi_dataset.by_name", "add_rows_api_enabled", "false"),
+					resource.TestCheckResourceAttrSet("data.powerbi_dataset.by_name", "web_url"),
+					resource.TestCheckResourceAttr("data.powerbi_dataset.by_id", "name", "Unit Test PBIX"),
+				),
+			},
+			{
+				Config: config + `
+				data "powerbi_dataset" "missing" {
+					workspace_id = powerbi_workspace.test.id
+					id = "00000000-0000-0000-0000-000000000001"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Dataset with id 00000000-0000-0000-0000-000000000001 not found"),
+			},
+		},
+	})
+}
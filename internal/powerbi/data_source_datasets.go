@@ -0,0 +1,120 @@
+package powerbi
+
+import (
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DataSourceDatasets represents the Power BI datasets within a workspace
+func DataSourceDatasets() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceDatasetsRead,
+
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Workspace ID containing the datasets.",
+			},
+			"datasets": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The datasets within the workspace.",
+				Elem: &schema.Resource{
+					Schema: datasetAttributesSchema(map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the dataset.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the dataset.",
+						},
+					}),
+				},
+			},
+		},
+	}
+}
+
+// datasetAttributesSchema adds the computed dataset attributes shared by the dataset data sources
+func datasetAttributesSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["web_url"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The web URL of the dataset.",
+	}
+	s["configured_by"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The owner of the dataset.",
+	}
+	s["target_storage_mode"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The storage mode of the dataset, for example `Abf` for import datasets or `PremiumFiles` for large datasets.",
+	}
+	s["add_rows_api_enabled"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether the dataset is a push dataset that rows can be added to.",
+	}
+	s["is_refreshable"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether the dataset can be refreshed.",
+	}
+	s["is_effective_identity_required"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether the dataset requires an effective identity when generating embed tokens.",
+	}
+	s["is_effective_identity_roles_required"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether the dataset requires roles when generating embed tokens.",
+	}
+	s["is_on_prem_gateway_required"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Computed:    true,
+		Description: "Whether the dataset requires an on-premises data gateway.",
+	}
+	return s
+}
+
+func flattenDataset(dataset powerbiapi.GetDatasetsInGroupResponseItem) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                                   dataset.ID,
+		"name":                                 dataset.Name,
+		"web_url":                              dataset.WebURL,
+		"configured_by":                        dataset.ConfiguredBy,
+		"target_storage_mode":                  dataset.TargetStorageMode,
+		"add_rows_api_enabled":                 dataset.AddRowsAPIEnabled,
+		"is_refreshable":                       dataset.IsRefreshable,
+		"is_effective_identity_required":       dataset.IsEffectiveIdentityRequired,
+		"is_effective_identity_roles_required": dataset.IsEffectiveIdentityRolesRequired,
+		"is_on_prem_gateway_required":          dataset.IsOnPremGatewayRequired,
+	}
+}
+
+func dataSourceDatasetsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasets, err := client.GetDatasetsInGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	results := make([]interface{}, 0, len(datasets.Value))
+	for _, dataset := range datasets.Value {
+		results = append(results, flattenDataset(dataset))
+	}
+
+	d.SetId(groupID)
+	d.Set("datasets", results)
+
+	return nil
+}
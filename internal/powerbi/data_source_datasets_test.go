@@ -0,0 +1,60 @@
+package powerbi
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceDatasets_basic(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = powerbi_workspace.test.id
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+
+	resource "powerbi_dataset" "test" {
+		workspace_id = powerbi_workspace.test.id
+		default_mode = "push"
+		name = "Unit Test Push Dataset"
+		depends_on = [powerbi_pbix.test]
+		table {
+			name = "table"
+			column {
+				name = "column"
+				data_type = "string"
+			}
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config: config + `
+				data "powerbi_datasets" "test" {
+					workspace_id = powerbi_workspace.test.id
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_datasets.test", "datasets.#", "2"),
+					resource.TestCheckResourceAttr("data.powerbi_datasets.test", "datasets.0.name", "Unit Test PBIX"),
+					resource.TestCheckResourceAttr("data.powerbi_datasets.test", "datasets.1.name", "Unit Test Push Dataset"),
+					resource.TestCheckResourceAttr("data.powerbi_datasets.test", "datasets.1.add_rows_api_enabled", "true"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,98 @@
+package powerbi
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DataSourceGateway represents a single Power BI gateway
+func DataSourceGateway() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceGatewayRead,
+
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "The ID of the gateway. Exactly one of `id` or `name` must be provided.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "The name of the gateway. Exactly one of `id` or `name` must be provided.",
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of gateway, for example `Resource` for an on-premises gateway.",
+			},
+			"gateway_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The connectivity status of the gateway.",
+			},
+			"gateway_annotation": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Metadata in JSON format describing the gateway.",
+			},
+			"public_key_exponent": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The exponent of the public key used to encrypt credentials for the gateway.",
+			},
+			"public_key_modulus": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The modulus of the public key used to encrypt credentials for the gateway.",
+			},
+		},
+	}
+}
+
+func dataSourceGatewayRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	gateways, err := client.GetGateways()
+	if err != nil {
+		return err
+	}
+
+	id := d.Get("id").(string)
+	name := d.Get("name").(string)
+
+	var matches []powerbiapi.GetGatewaysResponseItem
+	for _, gateway := range gateways.Value {
+		if (id != "" && strings.EqualFold(gateway.ID, id)) || (name != "" && gateway.Name == name) {
+			matches = append(matches, gateway)
+		}
+	}
+
+	if len(matches) == 0 {
+		if id != "" {
+			return fmt.Errorf("Gateway id %s not found or logged-in user is not a gateway admin", id)
+		}
+		return fmt.Errorf("Gateway with name '%s' not found or logged-in user is not a gateway admin", name)
+	}
+	if len(matches) > 1 {
+		return fmt.Errorf("Found %d gateways with name '%s'. Use id to select a single gateway", len(matches), name)
+	}
+
+	gateway := matches[0]
+	d.SetId(gateway.ID)
+	d.Set("name", gateway.Name)
+	d.Set("type", gateway.Type)
+	d.Set("gateway_status", gateway.GatewayStatus)
+	d.Set("gateway_annotation", gateway.GatewayAnnotation)
+	d.Set("public_key_exponent", gateway.PublicKey.Exponent)
+	d.Set("public_key_modulus", gateway.PublicKey.Modulus)
+
+	return nil
+}
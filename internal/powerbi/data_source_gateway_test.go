@@ -0,0 +1,53 @@
+package powerbi
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceGateway_basic(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	gateway := server.AddGateway(powerbiapitest.Gateway{Name: "Unit Test Gateway"})
+	server.AddGateway(powerbiapitest.Gateway{Name: "Other Gateway"})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "powerbi_gateway" "by_name" {
+					name = "Unit Test Gateway"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_gateway.by_name", "id", gateway.ID),
+					resource.TestCheckResourceAttr("data.powerbi_gateway.by_name", "type", "Resource"),
+					resource.TestCheckResourceAttr("data.powerbi_gateway.by_name", "gateway_status", "Live"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+				data "powerbi_gateway" "by_id" {
+					id = "%s"
+				}
+				`, gateway.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_gateway.by_id", "name", "Unit Test Gateway"),
+				),
+			},
+			{
+				Config: `
+				data "powerbi_gateway" "missing" {
+					name = "Missing Gateway"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Gateway with name 'Missing Gateway' not found"),
+			},
+		},
+	})
+}
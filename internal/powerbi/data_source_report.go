@@ -0,0 +1,84 @@
+package powerbi
+
+import (
+	"fmt"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DataSourceReport represents a single Power BI report
+func DataSourceReport() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceReportRead,
+
+		Schema: reportAttributesSchema(map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Workspace ID containing the report.",
+			},
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "ID of the report. Exactly one of `id` or `name` must be provided.",
+			},
+			"name": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "Name of the report. Exactly one of `id` or `name` must be provided.",
+			},
+		}),
+	}
+}
+
+func dataSourceReportRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+
+	var report powerbiapi.GetReportsInGroupResponseItem
+	if id, ok := d.GetOk("id"); ok {
+		resp, err := client.GetReportInGroup(groupID, id.(string))
+		if isHTTP404Error(err) {
+			return fmt.Errorf("Report with id %s not found in workspace %s", id, groupID)
+		}
+		if err != nil {
+			return err
+		}
+		report = powerbiapi.GetReportsInGroupResponseItem(*resp)
+	} else {
+		name := d.Get("name").(string)
+		reports, err := client.GetReportsInGroup(groupID)
+		if err != nil {
+			return err
+		}
+
+		var matches []powerbiapi.GetReportsInGroupResponseItem
+		for _, item := range reports.Value {
+			if item.Name == name {
+				matches = append(matches, item)
+			}
+		}
+		if len(matches) == 0 {
+			return fmt.Errorf("Report with name '%s' not found in workspace %s", name, groupID)
+		}
+		if len(matches) > 1 {
+			return fmt.Errorf("Found %d reports with name '%s' in workspace %s. Use id to select a single report", len(matches), name, groupID)
+		}
+		report = matches[0]
+	}
+
+	d.SetId(report.ID)
+	for key, value := range flattenReport(report) {
+		if key != "id" {
+			d.Set(key, value)
+		}
+	}
+
+	return nil
+}
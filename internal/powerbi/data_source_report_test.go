@@ -0,0 +1,64 @@
+package powerbi
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceReport_basic(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = powerbi_workspace.test.id
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config: config + `
+				data "powerbi_report" "by_name" {
+					workspace_id = powerbi_workspace.test.id
+					name = "Unit Test PBIX"
+				}
+
+				data "powerbi_report" "by_id" {
+					workspace_id = powerbi_workspace.test.id
+					id = powerbi_pbix.test.report_id
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.powerbi_report.by_name", "id", "powerbi_pbix.test", "report_id"),
+					resource.TestCheckResourceAttrPair("data.powerbi_report.by_name", "dataset_id", "powerbi_pbix.test", "dataset_id"),
+					resource.TestCheckResourceAttr("data.powerbi_report.by_name", "report_type", "PowerBIReport"),
+					resource.TestCheckResourceAttrSet("data.powerbi_report.by_name", "web_url"),
+					resource.TestCheckResourceAttrSet("data.powerbi_report.by_name", "embed_url"),
+					resource.TestCheckResourceAttr("data.powerbi_report.by_id", "name", "Unit Test PBIX"),
+				),
+			},
+			{
+				Config: config + `
+				data "powerbi_report" "missing" {
+					workspace_id = powerbi_workspace.test.id
+					name = "Missing Report"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Report with name 'Missing Report' not found"),
+			},
+		},
+	})
+}
@@ -0,0 +1,96 @@
+package powerbi
+
+import (
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// DataSourceReports represents the Power BI reports within a workspace
+func DataSourceReports() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceReportsRead,
+
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Workspace ID containing the reports.",
+			},
+			"reports": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The reports within the workspace.",
+				Elem: &schema.Resource{
+					Schema: reportAttributesSchema(map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the report.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the report.",
+						},
+					}),
+				},
+			},
+		},
+	}
+}
+
+// reportAttributesSchema adds the computed report attributes shared by the report data sources
+func reportAttributesSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["dataset_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "ID of the dataset the report is bound to.",
+	}
+	s["report_type"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The type of report, for example `PowerBIReport` or `PaginatedReport`.",
+	}
+	s["web_url"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The web URL of the report.",
+	}
+	s["embed_url"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The embed URL of the report.",
+	}
+	return s
+}
+
+func flattenReport(report powerbiapi.GetReportsInGroupResponseItem) map[string]interface{} {
+	return map[string]interface{}{
+		"id":          report.ID,
+		"name":        report.Name,
+		"dataset_id":  report.DatasetID,
+		"report_type": report.ReportType,
+		"web_url":     report.WebURL,
+		"embed_url":   report.EmbedURL,
+	}
+}
+
+func dataSourceReportsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	reports, err := client.GetReportsInGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	results := make([]interface{}, 0, len(reports.Value))
+	for _, report := range reports.Value {
+		results = append(results, flattenReport(report))
+	}
+
+	d.SetId(groupID)
+	d.Set("reports", results)
+
+	return nil
+}
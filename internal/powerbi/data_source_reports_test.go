@@ -0,0 +1,47 @@
+package powerbi
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceReports_basic(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		count = 2
+		workspace_id = powerbi_workspace.test.id
+		name = "Unit Test PBIX ${count.index}"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+			},
+			{
+				Config: config + `
+				data "powerbi_reports" "test" {
+					workspace_id = powerbi_workspace.test.id
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_reports.test", "reports.#", "2"),
+					resource.TestCheckResourceAttrSet("data.powerbi_reports.test", "reports.0.id"),
+					resource.TestCheckResourceAttrSet("data.powerbi_reports.test", "reports.0.dataset_id"),
+					resource.TestCheckResourceAttrSet("data.powerbi_reports.test", "reports.0.embed_url"),
+				),
+			},
+		},
+	})
+}
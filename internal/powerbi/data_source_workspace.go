@@ -1,6 +1,8 @@
 package powerbi
 
 import (
+	"fmt"
+
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 )
@@ -11,10 +13,19 @@ func DataSourceWorkspace() *schema.Resource {
 		Read: dataSourceWorkspaceRead,
 
 		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "ID of the workspace. Exactly one of `id` or `name` must be provided.",
+			},
 			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Name of the workspace.",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"id", "name"},
+				Description:  "Name of the workspace. Exactly one of `id` or `name` must be provided.",
 			},
 			"capacity_id": {
 				Type:        schema.TypeString,
@@ -22,28 +33,43 @@ func DataSourceWorkspace() *schema.Resource {
 				Computed:    true,
 				Description: "Capacity ID to be assigned to workspace.",
 			},
+			"is_on_dedicated_capacity": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the workspace is assigned to a dedicated capacity.",
+			},
 		},
 	}
 }
 
 func dataSourceWorkspaceRead(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*powerbiapi.Client)
-	name := d.Get("name").(string)
-	workspace, err := client.GetGroupByName(name)
+
+	var workspace *powerbiapi.GetGroupResponse
+	var err error
+	if id, ok := d.GetOk("id"); ok {
+		workspace, err = client.GetGroup(id.(string))
+		if err == nil && workspace == nil {
+			err = fmt.Errorf("Workspace with id %s not found or logged-in user doesn't have access to it", id)
+		}
+	} else {
+		name := d.Get("name").(string)
+		workspace, err = client.GetGroupByName(name)
+		if err == nil && workspace == nil {
+			err = fmt.Errorf("Workspace with name '%s' not found or logged-in user doesn't have access to it", name)
+		}
+	}
 	if err != nil {
 		return err
 	}
 
-	if workspace == nil {
-		d.SetId("")
+	d.SetId(workspace.ID)
+	d.Set("name", workspace.Name)
+	d.Set("is_on_dedicated_capacity", workspace.IsOnDedicatedCapacity)
+	if workspace.IsOnDedicatedCapacity {
+		d.Set("capacity_id", workspace.CapacityID)
 	} else {
-		d.SetId(workspace.ID)
-		d.Set("name", workspace.Name)
-		if workspace.IsOnDedicatedCapacity {
-			d.Set("capacity_id", workspace.CapacityID)
-		} else {
-			d.Set("capacity_id", "")
-		}
+		d.Set("capacity_id", "")
 	}
 
 	return nil
@@ -2,6 +2,8 @@ package powerbi
 
 import (
 	"fmt"
+	"os"
+	"regexp"
 	"testing"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
@@ -11,6 +13,11 @@ import (
 )
 
 func TestAccDataSourceWorkspace_basic(t *testing.T) {
+	// the workspace is created outside of the test case so needs the same guard
+	if os.Getenv(resource.TestEnvVar) == "" {
+		t.Skip(fmt.Sprintf("Acceptance tests skipped unless env '%s' set", resource.TestEnvVar))
+	}
+
 	workspaceSuffix := acctest.RandString(6)
 	var workspaceName = fmt.Sprintf("Acceptance Test Data Source Workspace %s - Basic", workspaceSuffix)
 
@@ -41,3 +48,74 @@ func TestAccDataSourceWorkspace_basic(t *testing.T) {
 		},
 	})
 }
+
+func TestUnitDataSourceWorkspace_basic(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				data "powerbi_workspace" "test" {
+					name = powerbi_workspace.test.name
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_workspace.test", "name", "Unit Test Workspace"),
+					resource.TestCheckResourceAttrPair("data.powerbi_workspace.test", "id", "powerbi_workspace.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitDataSourceWorkspace_byID(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				data "powerbi_workspace" "test" {
+					id = powerbi_workspace.test.id
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_workspace.test", "name", "Unit Test Workspace"),
+					resource.TestCheckResourceAttr("data.powerbi_workspace.test", "is_on_dedicated_capacity", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitDataSourceWorkspace_notFound(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				data "powerbi_workspace" "test" {
+					name = "Missing O'Brien Workspace"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Workspace with name 'Missing O'Brien Workspace' not found"),
+			},
+		},
+	})
+}
@@ -0,0 +1,86 @@
+package powerbi
+
+import (
+	"strconv"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/hashcode"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// number of workspaces requested at a time, the API returns at most 5000
+var workspacesPageSize = 5000
+
+// DataSourceWorkspaces represents the Power BI workspaces the user has access to
+func DataSourceWorkspaces() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceWorkspacesRead,
+
+		Schema: map[string]*schema.Schema{
+			"filter": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "OData filter to restrict the workspaces returned, for example `contains(name,'Sales')`.",
+			},
+			"workspaces": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The workspaces matching the filter.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the workspace.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the workspace.",
+						},
+						"capacity_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Capacity ID the workspace is assigned to.",
+						},
+						"is_on_dedicated_capacity": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the workspace is assigned to a dedicated capacity.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceWorkspacesRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	filter := d.Get("filter").(string)
+	workspaces, err := client.GetAllGroups(filter, workspacesPageSize)
+	if err != nil {
+		return err
+	}
+
+	results := make([]interface{}, 0, len(workspaces))
+	for _, workspace := range workspaces {
+		capacityID := ""
+		if workspace.IsOnDedicatedCapacity {
+			capacityID = workspace.CapacityID
+		}
+		results = append(results, map[string]interface{}{
+			"id":                       workspace.ID,
+			"name":                     workspace.Name,
+			"capacity_id":              capacityID,
+			"is_on_dedicated_capacity": workspace.IsOnDedicatedCapacity,
+		})
+	}
+
+	// data sources need an id, base it on the filter so it is stable between runs
+	d.SetId(strconv.Itoa(hashcode.String(filter)))
+	d.Set("workspaces", results)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package powerbi
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDataSourceWorkspaces_paging(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	// force multiple pages to be requested
+	defer func(pageSize int) { workspacesPageSize = pageSize }(workspacesPageSize)
+	workspacesPageSize = 2
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					count = 5
+					name  = "Unit Test Workspace ${count.index}"
+				}
+
+				resource "powerbi_workspace" "other" {
+					name = "Other Workspace"
+				}
+				`,
+			},
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					count = 5
+					name  = "Unit Test Workspace ${count.index}"
+				}
+
+				resource "powerbi_workspace" "other" {
+					name = "Other Workspace"
+				}
+
+				data "powerbi_workspaces" "all" {
+				}
+
+				data "powerbi_workspaces" "filtered" {
+					filter = "startswith(name,'Unit Test')"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.powerbi_workspaces.all", "workspaces.#", "6"),
+					resource.TestCheckResourceAttr("data.powerbi_workspaces.filtered", "workspaces.#", "5"),
+					resource.TestCheckResourceAttrSet("data.powerbi_workspaces.filtered", "workspaces.0.id"),
+					resource.TestCheckResourceAttr("data.powerbi_workspaces.filtered", "workspaces.0.is_on_dedicated_capacity", "false"),
+				),
+			},
+		},
+	})
+}
@@ -1,8 +1,12 @@
 package powerbi
 
 import (
+	"fmt"
+	"io/ioutil"
+
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 // Provider represents the powerbi terraform provider
@@ -11,22 +15,22 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"tenant_id": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_TENANT_ID", ""),
-				Description: "The Tenant ID for the tenant which contains the Azure Active Directory App Registration to use for performing Power BI REST API operations. This can also be sourced from the `POWERBI_TENANT_ID` Environment Variable",
+				Description: "The Tenant ID for the tenant which contains the Azure Active Directory App Registration to use for performing Power BI REST API operations. Required unless using `use_msi`, `use_cli` or `access_token`. This can also be sourced from the `POWERBI_TENANT_ID` Environment Variable",
 			},
 			"client_id": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_ID", ""),
-				Description: "Also called Application ID. The Client ID for the Azure Active Directory App Registration to use for performing Power BI REST API operations. This can also be sourced from the `POWERBI_CLIENT_ID` Environment Variable",
+				Description: "Also called Application ID. The Client ID for the Azure Active Directory App Registration to use for performing Power BI REST API operations. Required unless using `use_cli`, `access_token` or `use_msi` with a system assigned identity. This can also be sourced from the `POWERBI_CLIENT_ID` Environment Variable",
 			},
 			"client_secret": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_SECRET", ""),
-				Description: "Also called Application Secret. The Client Secret for the Azure Active Directory App Registration to use for performing Power BI REST API operations. This can also be sourced from the `POWERBI_CLIENT_SECRET` Environment Variable",
+				Description: "Also called Application Secret. The Client Secret for the Azure Active Directory App Registration to use for performing Power BI REST API operations. Required unless using `client_certificate_path`, `use_msi`, `use_oidc`, `use_cli` or `access_token`. This can also be sourced from the `POWERBI_CLIENT_SECRET` Environment Variable",
 			},
 			"username": {
 				Type:        schema.TypeString,
@@ -41,18 +45,116 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("POWERBI_PASSWORD", ""),
 				Description: "The password for the a Power BI user to use for performing Power BI REST API operations. If provided will use resource owner password credentials flow with delegate permissions. This can also be sourced from the `POWERBI_PASSWORD` Environment Variable",
 			},
+			"environment": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("POWERBI_ENVIRONMENT", "public"),
+				ValidateFunc: validation.StringInSlice(powerbiapi.EnvironmentNames(), true),
+				Description:  "The Power BI cloud to use. Any value from `public`, `usgov`, `usgovhigh`, `dod` or `china`. This can also be sourced from the `POWERBI_ENVIRONMENT` Environment Variable",
+			},
+			"api_endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_API_ENDPOINT", ""),
+				Description: "Overrides the Power BI REST API endpoint of the selected `environment`, for example `https://api.powerbi.com`. This can also be sourced from the `POWERBI_API_ENDPOINT` Environment Variable",
+			},
+			"authority_host": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_AUTHORITY_HOST", ""),
+				Description: "Overrides the Azure Active Directory authority host of the selected `environment`, for example `https://login.microsoftonline.com`. This can also be sourced from the `POWERBI_AUTHORITY_HOST` Environment Variable",
+			},
+			"fabric_api_endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_FABRIC_API_ENDPOINT", ""),
+				Description: "Overrides the Fabric REST API endpoint of the selected `environment`, for example `https://api.fabric.microsoft.com`. The Fabric REST API is only used to rename reports and datasets. This can also be sourced from the `POWERBI_FABRIC_API_ENDPOINT` Environment Variable",
+			},
+			"client_certificate_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_CERTIFICATE_PATH", ""),
+				Description: "The path to a PFX or PEM certificate for the Azure Active Directory App Registration. If provided will authenticate with the certificate instead of `client_secret`. PEM files must contain both the certificate and its RSA private key. This can also be sourced from the `POWERBI_CLIENT_CERTIFICATE_PATH` Environment Variable",
+			},
+			"client_certificate_password": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_CLIENT_CERTIFICATE_PASSWORD", ""),
+				Description: "The password for the PFX certificate in `client_certificate_path`. This can also be sourced from the `POWERBI_CLIENT_CERTIFICATE_PASSWORD` Environment Variable",
+			},
+			"use_msi": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_USE_MSI", false),
+				Description: "Authenticate using the managed identity of the Azure host terraform is running on. Set `client_id` to select a user assigned identity. This can also be sourced from the `POWERBI_USE_MSI` Environment Variable",
+			},
+			"msi_endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_MSI_ENDPOINT", ""),
+				Description: "Overrides the endpoint managed identity tokens are requested from. Defaults to the Azure Instance Metadata Service. This can also be sourced from the `POWERBI_MSI_ENDPOINT` Environment Variable",
+			},
+			"use_oidc": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_USE_OIDC", false),
+				Description: "Authenticate by exchanging an OpenID Connect token from a trusted identity provider, such as GitHub Actions, for an access token using workload identity federation. Requires `tenant_id`, `client_id` and either `oidc_token` or `oidc_token_file_path`. This can also be sourced from the `POWERBI_USE_OIDC` Environment Variable",
+			},
+			"oidc_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_OIDC_TOKEN", ""),
+				Description: "The OpenID Connect token to use when `use_oidc` is set. This can also be sourced from the `POWERBI_OIDC_TOKEN` Environment Variable",
+			},
+			"oidc_token_file_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_OIDC_TOKEN_FILE_PATH", ""),
+				Description: "The path to a file containing the OpenID Connect token to use when `use_oidc` is set. The file is reread whenever a new access token is needed. This can also be sourced from the `POWERBI_OIDC_TOKEN_FILE_PATH` Environment Variable",
+			},
+			"use_cli": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_USE_CLI", false),
+				Description: "Authenticate as the account logged into the Azure CLI with `az login`. If `tenant_id` is provided a token for that tenant is requested. This can also be sourced from the `POWERBI_USE_CLI` Environment Variable",
+			},
+			"access_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("POWERBI_ACCESS_TOKEN", ""),
+				Description: "A Power BI REST API access token that has already been acquired. The token is not refreshed so must remain valid for the duration of the terraform run. This can also be sourced from the `POWERBI_ACCESS_TOKEN` Environment Variable",
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"powerbi_workspace":        ResourceWorkspace(),
-			"powerbi_pbix":             ResourcePBIX(),
-			"powerbi_refresh_schedule": ResourceRefreshSchedule(),
-			"powerbi_workspace_access": ResourceGroupUsers(),
-			"powerbi_dataset":          ResourceDataset(),
+			"powerbi_workspace":                     ResourceWorkspace(),
+			"powerbi_pbix":                          ResourcePBIX(),
+			"powerbi_import":                        ResourceImport(),
+			"powerbi_refresh_schedule":              ResourceRefreshSchedule(),
+			"powerbi_workspace_access":              ResourceGroupUsers(),
+			"powerbi_dataset":                       ResourceDataset(),
+			"powerbi_report":                        ResourceReport(),
+			"powerbi_dataset_refresh":               ResourceDatasetRefresh(),
+			"powerbi_dataset_enhanced_refresh":      ResourceDatasetEnhancedRefresh(),
+			"powerbi_direct_query_refresh_schedule": ResourceDirectQueryRefreshSchedule(),
+			"powerbi_gateway_datasource":            ResourceGatewayDatasource(),
+			"powerbi_gateway_datasource_user":       ResourceGatewayDatasourceUser(),
+			"powerbi_datasource_credentials":        ResourceDatasourceCredentials(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"powerbi_workspace": DataSourceWorkspace(),
+			"powerbi_workspace":  DataSourceWorkspace(),
+			"powerbi_workspaces": DataSourceWorkspaces(),
+			"powerbi_capacity":   DataSourceCapacity(),
+			"powerbi_capacities": DataSourceCapacities(),
+			"powerbi_report":     DataSourceReport(),
+			"powerbi_reports":    DataSourceReports(),
+			"powerbi_dataset":    DataSourceDataset(),
+			"powerbi_datasets":   DataSourceDatasets(),
+			"powerbi_gateway":    DataSourceGateway(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -61,22 +163,105 @@ func Provider() *schema.Provider {
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 
+	environment, err := getEnvironment(d)
+	if err != nil {
+		return nil, err
+	}
+
+	tenantID := d.Get("tenant_id").(string)
+	clientID := d.Get("client_id").(string)
+
+	if accessToken, ok := d.GetOk("access_token"); ok {
+		return powerbiapi.NewClientWithAccessToken(environment, accessToken.(string))
+	}
+
+	if d.Get("use_cli").(bool) {
+		return powerbiapi.NewClientWithAzureCLIAuth(environment, tenantID)
+	}
+
+	if d.Get("use_msi").(bool) {
+		return powerbiapi.NewClientWithMSIAuth(
+			environment,
+			d.Get("msi_endpoint").(string),
+			clientID,
+		)
+	}
+
+	if tenantID == "" || clientID == "" {
+		return nil, fmt.Errorf("tenant_id and client_id are required unless use_msi, use_cli or access_token is set")
+	}
+
+	if d.Get("use_oidc").(bool) {
+		oidcToken := d.Get("oidc_token").(string)
+		oidcTokenFilePath := d.Get("oidc_token_file_path").(string)
+		if oidcToken == "" && oidcTokenFilePath == "" {
+			return nil, fmt.Errorf("oidc_token or oidc_token_file_path is required when use_oidc is set")
+		}
+		return powerbiapi.NewClientWithOIDCAuth(
+			environment,
+			tenantID,
+			clientID,
+			oidcToken,
+			oidcTokenFilePath,
+		)
+	}
+
+	if certificatePath, ok := d.GetOk("client_certificate_path"); ok {
+		certificateData, err := ioutil.ReadFile(certificatePath.(string))
+		if err != nil {
+			return nil, fmt.Errorf("Could not read client certificate: %s", err)
+		}
+		return powerbiapi.NewClientWithClientCertificateAuth(
+			environment,
+			tenantID,
+			clientID,
+			certificateData,
+			d.Get("client_certificate_password").(string),
+		)
+	}
+
+	clientSecret, clientSecretOk := d.GetOk("client_secret")
+	if !clientSecretOk {
+		return nil, fmt.Errorf("client_secret is required unless client_certificate_path, use_msi, use_oidc, use_cli or access_token is set")
+	}
+
 	username, usernameOk := d.GetOk("username")
 	password, passwordOk := d.GetOk("password")
 
 	if usernameOk && passwordOk {
 		return powerbiapi.NewClientWithPasswordAuth(
-			d.Get("tenant_id").(string),
-			d.Get("client_id").(string),
-			d.Get("client_secret").(string),
+			environment,
+			tenantID,
+			clientID,
+			clientSecret.(string),
 			username.(string),
 			password.(string),
 		)
 	}
 	return powerbiapi.NewClientWithClientCredentialAuth(
-		d.Get("tenant_id").(string),
-		d.Get("client_id").(string),
-		d.Get("client_secret").(string),
+		environment,
+		tenantID,
+		clientID,
+		clientSecret.(string),
 	)
 
 }
+
+func getEnvironment(d *schema.ResourceData) (powerbiapi.Environment, error) {
+	environment, err := powerbiapi.GetEnvironment(d.Get("environment").(string))
+	if err != nil {
+		return environment, err
+	}
+
+	if apiEndpoint, ok := d.GetOk("api_endpoint"); ok {
+		environment.APIEndpoint = apiEndpoint.(string)
+	}
+	if authorityHost, ok := d.GetOk("authority_host"); ok {
+		environment.AuthorityHost = authorityHost.(string)
+	}
+	if fabricAPIEndpoint, ok := d.GetOk("fabric_api_endpoint"); ok {
+		environment.FabricAPIEndpoint = fabricAPIEndpoint.(string)
+	}
+
+	return environment, nil
+}
@@ -2,9 +2,12 @@ package powerbi
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
@@ -26,6 +29,153 @@ func TestProvider_validate(t *testing.T) {
 	}
 }
 
+func TestProvider_environment(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"environment":  "usgovhigh",
+		"api_endpoint": "http://localhost:8080",
+	})
+
+	environment, err := getEnvironment(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if environment.APIEndpoint != "http://localhost:8080" {
+		t.Fatalf("expected api_endpoint override to be used, found '%s'", environment.APIEndpoint)
+	}
+	if environment.AuthorityHost != "https://login.microsoftonline.us" {
+		t.Fatalf("expected usgovhigh authority host, found '%s'", environment.AuthorityHost)
+	}
+}
+
+func TestProvider_fabricAPIEndpoint(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"environment": "usgovhigh",
+	})
+
+	environment, err := getEnvironment(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if environment.FabricAPIEndpoint != "" {
+		t.Fatalf("expected usgovhigh to have no fabric api endpoint, found '%s'", environment.FabricAPIEndpoint)
+	}
+
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"environment":         "usgovhigh",
+		"fabric_api_endpoint": "http://localhost:8081",
+	})
+
+	environment, err = getEnvironment(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if environment.FabricAPIEndpoint != "http://localhost:8081" {
+		t.Fatalf("expected fabric_api_endpoint override to be used, found '%s'", environment.FabricAPIEndpoint)
+	}
+}
+
+func TestProvider_msiAuth(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	testProviderConfigure(t, map[string]interface{}{
+		"api_endpoint": server.URL,
+		"use_msi":      true,
+		"msi_endpoint": server.MSIEndpoint(),
+		"client_id":    "user-assigned-identity",
+	})
+
+	if clientID := server.LastTokenRequest().Get("client_id"); clientID != "user-assigned-identity" {
+		t.Fatalf("expected user assigned identity to be requested, found '%s'", clientID)
+	}
+}
+
+func TestProvider_oidcAuth(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	tokenFile, err := ioutil.TempFile("", "oidc-token")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(tokenFile.Name())
+	tokenFile.WriteString("federated-token\n")
+	tokenFile.Close()
+
+	testProviderConfigure(t, map[string]interface{}{
+		"api_endpoint":         server.URL,
+		"authority_host":       server.URL,
+		"tenant_id":            "tenant",
+		"client_id":            "client",
+		"client_secret":        "",
+		"use_oidc":             true,
+		"oidc_token_file_path": tokenFile.Name(),
+	})
+
+	if assertion := server.LastTokenRequest().Get("client_assertion"); assertion != "federated-token" {
+		t.Fatalf("expected federated token to be used as the client assertion, found '%s'", assertion)
+	}
+}
+
+func TestProvider_clientCertificateAuth(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	testProviderConfigure(t, map[string]interface{}{
+		"api_endpoint":                server.URL,
+		"authority_host":              server.URL,
+		"tenant_id":                   "tenant",
+		"client_id":                   "client",
+		"client_secret":               "",
+		"client_certificate_path":     "../powerbiapi/client_certificate_test.pfx",
+		"client_certificate_password": "password",
+	})
+
+	tokenRequest := server.LastTokenRequest()
+	if tokenRequest.Get("client_secret") != "" || tokenRequest.Get("client_assertion") == "" {
+		t.Fatalf("expected a client assertion to be used instead of a client secret")
+	}
+}
+
+func TestProvider_accessTokenAuth(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	testProviderConfigure(t, map[string]interface{}{
+		"api_endpoint": server.URL,
+		"access_token": server.IssueToken(),
+	})
+
+	if issued := server.TokensIssued(); issued != 1 {
+		t.Fatalf("expected the provided access token to be used, found %d tokens issued", issued)
+	}
+}
+
+func TestProvider_clientSecretRequired(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"tenant_id":     "tenant",
+		"client_id":     "client",
+		"client_secret": "",
+	})
+
+	if _, err := providerConfigure(d); err == nil {
+		t.Fatalf("expected an error when no client_secret or alternate authentication is provided")
+	}
+}
+
+// testProviderConfigure configures the provider and makes a request to check the client can authenticate
+func testProviderConfigure(t *testing.T, raw map[string]interface{}) {
+	d := schema.TestResourceDataRaw(t, Provider().Schema, raw)
+	client, err := providerConfigure(d)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := client.(*powerbiapi.Client).GetGroups("", -1, 0); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	requiredEnvs := []string{
 		"POWERBI_TENANT_ID",
@@ -38,3 +188,19 @@ func testAccPreCheck(t *testing.T) {
 		}
 	}
 }
+
+// testUnitSetup starts a fake Power BI service and configures testAccProvider to use it, allowing
+// resources to be tested without a tenant. The returned func stops the fake and restores the provider
+func testUnitSetup(t *testing.T) (*powerbiapitest.Server, func()) {
+	server := powerbiapitest.NewServer()
+
+	configureFunc := testAccProvider.ConfigureFunc
+	testAccProvider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return server.Client()
+	}
+
+	return server, func() {
+		testAccProvider.ConfigureFunc = configureFunc
+		server.Close()
+	}
+}
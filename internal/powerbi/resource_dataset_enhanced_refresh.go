@@ -0,0 +1,220 @@
+package powerbi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// ResourceDatasetEnhancedRefresh represents an on-demand enhanced refresh of tables and partitions in a Premium Power BI dataset
+func ResourceDatasetEnhancedRefresh() *schema.Resource {
+	return &schema.Resource{
+		Create: createDatasetEnhancedRefresh,
+		Read:   readDatasetEnhancedRefresh,
+		Delete: deleteDatasetEnhancedRefresh,
+
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Description: "Workspace ID in which the dataset was deployed. The workspace must be on a Premium or Embedded capacity.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"dataset_id": {
+				Type:        schema.TypeString,
+				Description: "The ID of the dataset to refresh.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Arbitrary map of values that, when changed, will trigger a new refresh.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Description:  "The type of processing to perform. Should be one of `Full`, `ClearValues`, `Calculate`, `DataOnly`, `Automatic` or `Defragment`.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "Full",
+				ValidateFunc: validation.StringInSlice([]string{"Full", "ClearValues", "Calculate", "DataOnly", "Automatic", "Defragment"}, false),
+			},
+			"commit_mode": {
+				Type:         schema.TypeString,
+				Description:  "Determines if objects are committed in batches or only when complete. Should be either `transactional` or `partialBatch`.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "transactional",
+				ValidateFunc: validation.StringInSlice([]string{"transactional", "partialBatch"}, false),
+			},
+			"max_parallelism": {
+				Type:         schema.TypeInt,
+				Description:  "The maximum number of threads on which to run parallel processing commands.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      10,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"retry_count": {
+				Type:         schema.TypeInt,
+				Description:  "Number of times the operation will retry before failing.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"apply_refresh_policy": {
+				Type:        schema.TypeBool,
+				Description: "If an incremental refresh policy is defined, determines whether to apply the policy.",
+				Optional:    true,
+				ForceNew:    true,
+				Default:     true,
+			},
+			"effective_date": {
+				Type:        schema.TypeString,
+				Description: "If an incremental refresh policy is applied, the date used to determine the rolling window ranges. If not set the current date is used.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"object": {
+				Type:        schema.TypeList,
+				Description: "Tables or partitions to refresh. If not set the entire dataset is refreshed.",
+				Optional:    true,
+				ForceNew:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"table": {
+							Type:        schema.TypeString,
+							Description: "The table to refresh.",
+							Required:    true,
+							ForceNew:    true,
+						},
+						"partition": {
+							Type:        schema.TypeString,
+							Description: "The partition of the table to refresh. If not set all partitions of the table are refreshed.",
+							Optional:    true,
+							ForceNew:    true,
+						},
+					},
+				},
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "The status of the refresh.",
+				Computed:    true,
+			},
+			"start_time": {
+				Type:        schema.TypeString,
+				Description: "The time the refresh started.",
+				Computed:    true,
+			},
+			"end_time": {
+				Type:        schema.TypeString,
+				Description: "The time the refresh completed.",
+				Computed:    true,
+			},
+			"number_of_attempts": {
+				Type:        schema.TypeInt,
+				Description: "The number of attempts the refresh took to complete.",
+				Computed:    true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func createDatasetEnhancedRefresh(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasetID := d.Get("dataset_id").(string)
+
+	request := powerbiapi.EnhancedRefreshDatasetInGroupRequest{
+		Type:               d.Get("type").(string),
+		CommitMode:         d.Get("commit_mode").(string),
+		MaxParallelism:     d.Get("max_parallelism").(int),
+		RetryCount:         d.Get("retry_count").(int),
+		ApplyRefreshPolicy: convertBoolToPointer(d.Get("apply_refresh_policy").(bool)),
+		EffectiveDate:      d.Get("effective_date").(string),
+	}
+	for _, object := range d.Get("object").([]interface{}) {
+		objectMap := object.(map[string]interface{})
+		request.Objects = append(request.Objects, powerbiapi.EnhancedRefreshDatasetInGroupRequestObject{
+			Table:     objectMap["table"].(string),
+			Partition: objectMap["partition"].(string),
+		})
+	}
+
+	resp, err := client.EnhancedRefreshDatasetInGroup(groupID, datasetID, request)
+	if err != nil {
+		return err
+	}
+
+	details, err := client.WaitForEnhancedRefreshInGroupToComplete(groupID, datasetID, resp.RefreshID, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		// leaving a timed out refresh running would block later refreshes of the dataset
+		if details != nil && isRefreshInProgress(details.Status) {
+			if cancelErr := client.CancelRefreshInGroup(groupID, datasetID, resp.RefreshID); cancelErr != nil {
+				return fmt.Errorf("%s. Cancelling the refresh also failed: %s", err, cancelErr)
+			}
+			return fmt.Errorf("%s. The refresh has been cancelled", err)
+		}
+		return err
+	}
+
+	d.SetId(resp.RefreshID)
+	setDatasetEnhancedRefresh(d, details)
+
+	return nil
+}
+
+func readDatasetEnhancedRefresh(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasetID := d.Get("dataset_id").(string)
+
+	details, err := client.GetRefreshExecutionDetailsInGroup(groupID, datasetID, d.Id())
+	if isHTTP404Error(err) {
+		// the service only keeps a limited history, older refreshes keep the values they completed with
+		// unless the dataset itself has been removed
+		_, err = client.GetDatasetInGroup(groupID, datasetID)
+		if isHTTP404Error(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	setDatasetEnhancedRefresh(d, details)
+
+	return nil
+}
+
+func deleteDatasetEnhancedRefresh(d *schema.ResourceData, meta interface{}) error {
+	// a refresh cannot be undone, it is only removed from state
+	return nil
+}
+
+func setDatasetEnhancedRefresh(d *schema.ResourceData, details *powerbiapi.GetRefreshExecutionDetailsInGroupResponse) {
+	d.Set("status", details.Status)
+	d.Set("start_time", formatRefreshTime(details.StartTime))
+	d.Set("end_time", formatRefreshTime(details.EndTime))
+	d.Set("number_of_attempts", details.NumberOfAttempts)
+}
+
+func isRefreshInProgress(status string) bool {
+	return status == "Unknown" || status == "NotStarted" || status == "InProgress"
+}
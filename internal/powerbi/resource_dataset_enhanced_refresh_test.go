@@ -0,0 +1,181 @@
+package powerbi
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func testUnitEnhancedRefreshConfig(capacityID string, refreshConfig string) string {
+	return fmt.Sprintf(`
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+		capacity_id = "%s"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = powerbi_workspace.test.id
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+	%s
+	`, capacityID, refreshConfig)
+}
+
+func TestUnitDatasetEnhancedRefresh_basic(t *testing.T) {
+	var refreshID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	capacity := server.AddCapacity(powerbiapitest.Capacity{DisplayName: "Unit Test Capacity", SKU: "P1"})
+
+	refreshConfigTemplate := `
+	resource "powerbi_dataset_enhanced_refresh" "test" {
+		workspace_id = powerbi_workspace.test.id
+		dataset_id = powerbi_pbix.test.dataset_id
+		type = "DataOnly"
+		commit_mode = "partialBatch"
+		max_parallelism = 2
+		retry_count = 1
+		object {
+			table = "Sales"
+			partition = "Sales-2020"
+		}
+		object {
+			table = "Customers"
+		}
+		triggers = {
+			version = "%s"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitEnhancedRefreshConfig(capacity.ID, fmt.Sprintf(refreshConfigTemplate, "1")),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_dataset_enhanced_refresh.test", "id", &refreshID),
+					resource.TestCheckResourceAttr("powerbi_dataset_enhanced_refresh.test", "status", "Completed"),
+					resource.TestCheckResourceAttr("powerbi_dataset_enhanced_refresh.test", "number_of_attempts", "1"),
+					resource.TestCheckResourceAttrSet("powerbi_dataset_enhanced_refresh.test", "end_time"),
+					testCheckEnhancedRefreshDetails("powerbi_dataset_enhanced_refresh.test", "DataOnly", "partialBatch", []string{"Sales/Sales-2020", "Customers/"}),
+				),
+			},
+			// changed triggers start a new refresh
+			{
+				Config: testUnitEnhancedRefreshConfig(capacity.ID, fmt.Sprintf(refreshConfigTemplate, "2")),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceAttrChanged("powerbi_dataset_enhanced_refresh.test", "id", &refreshID),
+					testCheckDatasetRefreshes("powerbi_dataset_enhanced_refresh.test", "dataset_id", 2),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitDatasetEnhancedRefresh_cancelledOnTimeout(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	capacity := server.AddCapacity(powerbiapitest.Capacity{DisplayName: "Unit Test Capacity", SKU: "P1"})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testUnitEnhancedRefreshConfig(capacity.ID, ""),
+			},
+			{
+				PreConfig: func() {
+					server.HoldRefreshes = true
+				},
+				Config: testUnitEnhancedRefreshConfig(capacity.ID, `
+				resource "powerbi_dataset_enhanced_refresh" "test" {
+					workspace_id = powerbi_workspace.test.id
+					dataset_id = powerbi_pbix.test.dataset_id
+					timeouts {
+						create = "1s"
+					}
+				}
+				`),
+				ExpectError: regexp.MustCompile("Timed out waiting for refresh to complete.*The refresh has been cancelled"),
+			},
+			{
+				PreConfig: func() {
+					server.HoldRefreshes = false
+				},
+				Config: testUnitEnhancedRefreshConfig(capacity.ID, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckLatestRefreshStatus("powerbi_pbix.test", "Cancelled"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckEnhancedRefreshDetails(resourceName string, expectedType string, expectedCommitMode string, expectedObjects []string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		details, err := client.GetRefreshExecutionDetailsInGroup(rs.Primary.Attributes["workspace_id"], rs.Primary.Attributes["dataset_id"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+
+		if details.Type != expectedType || details.CommitMode != expectedCommitMode {
+			return fmt.Errorf("Expecting refresh of type '%s' with commit mode '%s'. Found type '%s' with commit mode '%s'", expectedType, expectedCommitMode, details.Type, details.CommitMode)
+		}
+
+		var objects []string
+		for _, object := range details.Objects {
+			objects = append(objects, object.Table+"/"+object.Partition)
+		}
+		if fmt.Sprint(objects) != fmt.Sprint(expectedObjects) {
+			return fmt.Errorf("Expecting refresh objects %v. Found %v", expectedObjects, objects)
+		}
+		return nil
+	}
+}
+
+func testCheckLatestRefreshStatus(pbixResourceName string, expectedStatus string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[pbixResourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", pbixResourceName)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		history, err := client.GetRefreshHistoryInGroup(rs.Primary.Attributes["workspace_id"], rs.Primary.Attributes["dataset_id"], 1)
+		if err != nil {
+			return err
+		}
+		if len(history.Value) != 1 || history.Value[0].Status != expectedStatus {
+			return fmt.Errorf("Expecting the latest refresh to have status '%s'. Found %v", expectedStatus, history.Value)
+		}
+		return nil
+	}
+}
+
+func testCheckResourceAttrChanged(resourceName string, attributeName string, previousValue *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+		if rs.Primary.Attributes[attributeName] == *previousValue {
+			return fmt.Errorf("Expecting %s.%s to change from '%s'", resourceName, attributeName, *previousValue)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,138 @@
+package powerbi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// ResourceDatasetRefresh represents an on-demand refresh of a Power BI dataset
+func ResourceDatasetRefresh() *schema.Resource {
+	return &schema.Resource{
+		Create: createDatasetRefresh,
+		Read:   readDatasetRefresh,
+		Delete: deleteDatasetRefresh,
+
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Description: "Workspace ID in which the dataset was deployed.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"dataset_id": {
+				Type:        schema.TypeString,
+				Description: "The ID of the dataset to refresh.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"triggers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description: "Arbitrary map of values that, when changed, will trigger a new refresh.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"notify_option": {
+				Type:         schema.TypeString,
+				Description:  "The notification option when the refresh completes. Should be one of `NoNotification`, `MailOnFailure` or `MailOnCompletion`. Mail notifications are only supported when using password authentication.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "NoNotification",
+				ValidateFunc: validation.StringInSlice([]string{"NoNotification", "MailOnFailure", "MailOnCompletion"}, false),
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Description: "The status of the refresh.",
+				Computed:    true,
+			},
+			"start_time": {
+				Type:        schema.TypeString,
+				Description: "The time the refresh started.",
+				Computed:    true,
+			},
+			"end_time": {
+				Type:        schema.TypeString,
+				Description: "The time the refresh completed.",
+				Computed:    true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+		},
+	}
+}
+
+func createDatasetRefresh(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasetID := d.Get("dataset_id").(string)
+
+	resp, err := client.RefreshDatasetInGroup(groupID, datasetID, powerbiapi.RefreshDatasetInGroupRequest{
+		NotifyOption: d.Get("notify_option").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	refresh, err := client.WaitForRefreshInGroupToComplete(groupID, datasetID, resp, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	// the request ID identifies the refresh in the history, so without it the refresh cannot be tracked
+	if refresh.RequestID == "" {
+		return fmt.Errorf("Unable to determine the request ID of the refresh of dataset %s", datasetID)
+	}
+
+	d.SetId(refresh.RequestID)
+	setDatasetRefresh(d, refresh)
+
+	return nil
+}
+
+func readDatasetRefresh(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	history, err := client.GetRefreshHistoryInGroup(d.Get("workspace_id").(string), d.Get("dataset_id").(string), 0)
+	if isHTTP404Error(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// the service only keeps a limited history, older refreshes keep the values they completed with
+	for i := range history.Value {
+		if history.Value[i].RequestID == d.Id() {
+			setDatasetRefresh(d, &history.Value[i])
+		}
+	}
+
+	return nil
+}
+
+func deleteDatasetRefresh(d *schema.ResourceData, meta interface{}) error {
+	// a refresh cannot be undone, it is only removed from state
+	return nil
+}
+
+func setDatasetRefresh(d *schema.ResourceData, refresh *powerbiapi.GetRefreshHistoryInGroupResponseItem) {
+	d.Set("status", refresh.Status)
+	d.Set("start_time", formatRefreshTime(refresh.StartTime))
+	d.Set("end_time", formatRefreshTime(refresh.EndTime))
+}
+
+func formatRefreshTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,177 @@
+package powerbi
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestUnitDatasetRefresh_basic(t *testing.T) {
+	var refreshID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = powerbi_workspace.test.id
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+
+	resource "powerbi_dataset_refresh" "test" {
+		workspace_id = powerbi_workspace.test.id
+		dataset_id = powerbi_pbix.test.dataset_id
+		triggers = {
+			version = "%s"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_dataset_refresh.test", "id", &refreshID),
+					resource.TestCheckResourceAttr("powerbi_dataset_refresh.test", "status", "Completed"),
+					resource.TestCheckResourceAttrSet("powerbi_dataset_refresh.test", "start_time"),
+					resource.TestCheckResourceAttrSet("powerbi_dataset_refresh.test", "end_time"),
+					testCheckDatasetRefreshes("powerbi_dataset_refresh.test", "dataset_id", 1),
+				),
+			},
+			// unchanged triggers do not refresh again
+			{
+				Config: fmt.Sprintf(configTemplate, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_dataset_refresh.test", "id", &refreshID),
+					testCheckDatasetRefreshes("powerbi_dataset_refresh.test", "dataset_id", 1),
+				),
+			},
+			// changed triggers start a new refresh
+			{
+				Config: fmt.Sprintf(configTemplate, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckDatasetRefreshes("powerbi_dataset_refresh.test", "dataset_id", 2),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitDatasetRefresh_withoutRequestID(t *testing.T) {
+	var refreshID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = powerbi_workspace.test.id
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+
+	resource "powerbi_dataset_refresh" "test" {
+		workspace_id = powerbi_workspace.test.id
+		dataset_id = powerbi_pbix.test.dataset_id
+		triggers = {
+			version = "%s"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_dataset_refresh.test", "id", &refreshID),
+				),
+			},
+			// the earlier completed refresh is not mistaken for the new refresh before it appears in the history
+			{
+				PreConfig: func() {
+					server.OmitRefreshRequestID = true
+					server.RefreshHistoryDelay = 1
+				},
+				Config: fmt.Sprintf(configTemplate, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceAttrNotEquals("powerbi_dataset_refresh.test", "id", &refreshID),
+					resource.TestCheckResourceAttr("powerbi_dataset_refresh.test", "status", "Completed"),
+					testCheckDatasetRefreshes("powerbi_dataset_refresh.test", "dataset_id", 2),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitDatasetRefresh_failed(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.RefreshServiceException = `{"errorCode":"ModelRefreshFailed_CredentialsNotSpecified"}`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_pbix" "test" {
+					workspace_id = powerbi_workspace.test.id
+					name = "Unit Test PBIX"
+					source = "./resource_pbix_test_sample1.pbix"
+				}
+
+				resource "powerbi_dataset_refresh" "test" {
+					workspace_id = powerbi_workspace.test.id
+					dataset_id = powerbi_pbix.test.dataset_id
+				}
+				`,
+				ExpectError: regexp.MustCompile("Refresh completed with status 'Failed'.*ModelRefreshFailed_CredentialsNotSpecified"),
+			},
+		},
+	})
+}
+
+func testCheckDatasetRefreshes(resourceName string, datasetAttribute string, expectedCount int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		history, err := client.GetRefreshHistoryInGroup(rs.Primary.Attributes["workspace_id"], rs.Primary.Attributes[datasetAttribute], 0)
+		if err != nil {
+			return err
+		}
+		if len(history.Value) != expectedCount {
+			return fmt.Errorf("Expecting %d refreshes of dataset %s. Found %d", expectedCount, rs.Primary.Attributes[datasetAttribute], len(history.Value))
+		}
+		for _, refresh := range history.Value {
+			if refresh.Status != "Completed" {
+				return fmt.Errorf("Expecting refresh %s to be completed. Found status '%s'", refresh.RequestID, refresh.Status)
+			}
+		}
+		return nil
+	}
+}
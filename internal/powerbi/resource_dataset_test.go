@@ -437,6 +437,59 @@ func TestAccDataset_forceNew(t *testing.T) {
 	})
 }
 
+func TestUnitDataset_basic(t *testing.T) {
+	var datasetID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+	resource "powerbi_dataset" "test" {
+		workspace_id = powerbi_workspace.test.id
+		default_mode = "push"
+		name = "Unit Test Dataset"
+
+		table {
+			name = "entries"
+			column {
+				name = "entryId"
+				data_type = "string"
+			}
+			column {
+				name = "entryValue"
+				data_type = "%s"
+			}
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "int64"),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_dataset.test", "id", &datasetID),
+					testDatsetExistsWithName("powerbi_dataset.test", "Unit Test Dataset"),
+					testPushDataSuccessful("powerbi_dataset.test", "entries", []map[string]interface{}{
+						{"entryId": "first", "entryValue": 1},
+					}),
+				),
+			},
+			// changing an existing table updates in place
+			{
+				Config: fmt.Sprintf(config, "decimal"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_dataset.test", "id", &datasetID),
+				),
+			},
+		},
+	})
+}
+
 func testDatsetExistsWithName(rn string, expectedName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -0,0 +1,169 @@
+package powerbi
+
+import (
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// ResourceDatasourceCredentials represents the credentials of a datasource used by a Power BI dataset
+func ResourceDatasourceCredentials() *schema.Resource {
+	resourceSchema := credentialsSchema()
+	resourceSchema["gateway_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The ID of the gateway the datasource is on. Datasources that do not use an on-premises gateway still report the ID of their cloud gateway.",
+		Required:    true,
+		ForceNew:    true,
+	}
+	resourceSchema["datasource_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The ID of the datasource.",
+		Required:    true,
+		ForceNew:    true,
+	}
+
+	return &schema.Resource{
+		Create: setDatasourceCredentials,
+		Read:   readDatasourceCredentials,
+		Update: setDatasourceCredentials,
+		Delete: deleteDatasourceCredentials,
+
+		Schema: resourceSchema,
+	}
+}
+
+// credentialsSchema returns the arguments used to provide credentials for a datasource
+func credentialsSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"credential_type": {
+			Type:         schema.TypeString,
+			Description:  "The type of credentials used to connect to the datasource. Any value from `Anonymous`, `Basic`, `Key`, `OAuth2` or `Windows`.",
+			Required:     true,
+			ValidateFunc: validation.StringInSlice([]string{"Anonymous", "Basic", "Key", "OAuth2", "Windows"}, false),
+		},
+		"username": {
+			Type:        schema.TypeString,
+			Description: "The username for `Basic` or `Windows` credentials.",
+			Optional:    true,
+		},
+		"password": {
+			Type:        schema.TypeString,
+			Description: "The password for `Basic` or `Windows` credentials.",
+			Optional:    true,
+			Sensitive:   true,
+		},
+		"key": {
+			Type:        schema.TypeString,
+			Description: "The key for `Key` credentials.",
+			Optional:    true,
+			Sensitive:   true,
+		},
+		"access_token": {
+			Type:        schema.TypeString,
+			Description: "The access token for `OAuth2` credentials.",
+			Optional:    true,
+			Sensitive:   true,
+		},
+		"encrypted_connection": {
+			Type:         schema.TypeString,
+			Description:  "Whether to encrypt the connection to the datasource. Either `Encrypted` or `NotEncrypted`.",
+			Optional:     true,
+			Default:      "Encrypted",
+			ValidateFunc: validation.StringInSlice([]string{"Encrypted", "NotEncrypted"}, false),
+		},
+		"privacy_level": {
+			Type:         schema.TypeString,
+			Description:  "The privacy level of the datasource. Any value from `None`, `Public`, `Organizational` or `Private`.",
+			Optional:     true,
+			Default:      "None",
+			ValidateFunc: validation.StringInSlice([]string{"None", "Public", "Organizational", "Private"}, false),
+		},
+	}
+}
+
+func setDatasourceCredentials(d *schema.ResourceData, meta interface{}) error {
+	gatewayID := d.Get("gateway_id").(string)
+	datasourceID := d.Get("datasource_id").(string)
+
+	err := updateDatasourceCredentials(meta, gatewayID, datasourceID, map[string]interface{}{
+		"credential_type":      d.Get("credential_type"),
+		"username":             d.Get("username"),
+		"password":             d.Get("password"),
+		"key":                  d.Get("key"),
+		"access_token":         d.Get("access_token"),
+		"encrypted_connection": d.Get("encrypted_connection"),
+		"privacy_level":        d.Get("privacy_level"),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(datasourceID)
+
+	return readDatasourceCredentials(d, meta)
+}
+
+func readDatasourceCredentials(d *schema.ResourceData, meta interface{}) error {
+	// credentials cannot be read back from the service, so the last values set are kept
+	return nil
+}
+
+func deleteDatasourceCredentials(d *schema.ResourceData, meta interface{}) error {
+	// datasources cannot be left without credentials, so the last credentials set are left as is
+	return nil
+}
+
+// updateDatasourceCredentials sets the credentials of a datasource, described by the arguments of credentialsSchema
+func updateDatasourceCredentials(meta interface{}, gatewayID string, datasourceID string, credentialsObj map[string]interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	credentialType := credentialsObj["credential_type"].(string)
+	credentials, err := powerbiapi.Credentials{
+		CredentialType: credentialType,
+		Username:       credentialsObj["username"].(string),
+		Password:       credentialsObj["password"].(string),
+		Key:            credentialsObj["key"].(string),
+		AccessToken:    credentialsObj["access_token"].(string),
+	}.JSON()
+	if err != nil {
+		return err
+	}
+
+	credentials, encryptionAlgorithm, err := encryptCredentialsForGateway(client, gatewayID, credentialType, credentials)
+	if err != nil {
+		return err
+	}
+
+	return client.UpdateGatewayDatasource(gatewayID, datasourceID, powerbiapi.UpdateGatewayDatasourceRequest{
+		CredentialDetails: powerbiapi.UpdateGatewayDatasourceRequestCredentialDetails{
+			CredentialType:      credentialType,
+			Credentials:         credentials,
+			EncryptedConnection: credentialsObj["encrypted_connection"].(string),
+			EncryptionAlgorithm: encryptionAlgorithm,
+			PrivacyLevel:        credentialsObj["privacy_level"].(string),
+		},
+	})
+}
+
+// encryptCredentialsForGateway encrypts credentials with the public key of an on-premises gateway. Credentials
+// for cloud datasources, whose gateways cannot be retrieved, and anonymous credentials are sent unencrypted.
+// The credentials are returned along with the encryption algorithm to report to the service
+func encryptCredentialsForGateway(client *powerbiapi.Client, gatewayID string, credentialType string, credentials string) (string, string, error) {
+	if credentialType == "Anonymous" {
+		return credentials, "None", nil
+	}
+
+	gateway, err := client.GetGateway(gatewayID)
+	if isHTTP404Error(err) {
+		return credentials, "None", nil
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	encrypted, err := powerbiapi.EncryptCredentials(credentials, gateway.PublicKey.Exponent, gateway.PublicKey.Modulus)
+	if err != nil {
+		return "", "", err
+	}
+	return encrypted, "RSA-OAEP", nil
+}
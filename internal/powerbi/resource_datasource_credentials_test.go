@@ -0,0 +1,61 @@
+package powerbi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+func TestUnitDatasourceCredentials_cloud(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.DefaultDatasources = []powerbiapitest.Datasource{
+		{
+			DatasourceID:      "3b7a53f1-8f2c-4d5e-9a6b-1c2d3e4f5a6b",
+			GatewayID:         "9c8d7e6f-5a4b-4c3d-8e2f-1a0b9c8d7e6f",
+			DatasourceType:    "OData",
+			ConnectionDetails: map[string]string{"url": "https://contoso.com/odata"},
+		},
+	}
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+
+	resource "powerbi_datasource_credentials" "test" {
+		gateway_id = "9c8d7e6f-5a4b-4c3d-8e2f-1a0b9c8d7e6f"
+		datasource_id = "3b7a53f1-8f2c-4d5e-9a6b-1c2d3e4f5a6b"
+		credential_type = "OAuth2"
+		access_token = "%s"
+		depends_on = [powerbi_pbix.test]
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "first-token"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckGatewayDatasourceCredentials(server, "powerbi_datasource_credentials.test", `{"credentialData":[{"name":"accessToken","value":"first-token"}]}`),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, "second-token"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckGatewayDatasourceCredentials(server, "powerbi_datasource_credentials.test", `{"credentialData":[{"name":"accessToken","value":"second-token"}]}`),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,148 @@
+package powerbi
+
+import (
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// ResourceDirectQueryRefreshSchedule represents a Power BI DirectQuery or LiveConnection refresh schedule
+func ResourceDirectQueryRefreshSchedule() *schema.Resource {
+	return &schema.Resource{
+		Create: createDirectQueryRefreshSchedule,
+		Read:   readDirectQueryRefreshSchedule,
+		Update: updateDirectQueryRefreshSchedule,
+		Delete: deleteDirectQueryRefreshSchedule,
+
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Description: "Workspace ID in which the dataset was deployed.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"dataset_id": {
+				Type:        schema.TypeString,
+				Description: "The ID for the DirectQuery or LiveConnection dataset.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"frequency": {
+				Type:         schema.TypeInt,
+				Description:  "The interval in minutes between refreshes. Should be one of `15`, `30`, `60`, `120` or `180`. Exactly one of `frequency` or `days` and `times` must be provided.",
+				Optional:     true,
+				ExactlyOneOf: []string{"frequency", "days"},
+				ValidateFunc: validation.IntInSlice([]int{15, 30, 60, 120, 180}),
+			},
+			"days": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description:  "The list of days of the week when the schedule should refresh. Exactly one of `frequency` or `days` and `times` must be provided.",
+				Optional:     true,
+				ExactlyOneOf: []string{"frequency", "days"},
+				RequiredWith: []string{"times"},
+			},
+			"times": {
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description:  "The list of times on the day the schedule should refresh. Times should be in the format HH:00 or HH:30 i.e. Hour should be two digits and minutes must either be on the full or half hour.",
+				Optional:     true,
+				RequiredWith: []string{"days"},
+			},
+			"local_time_zone_id": {
+				Type:        schema.TypeString,
+				Description: "The name of the timezone to use. See Name of Time Zone column in [Microsoft Time Zone Index Values](https://support.microsoft.com/en-gb/help/973627/microsoft-time-zone-index-values).",
+				Optional:    true,
+				Default:     "UTC",
+			},
+		},
+	}
+}
+
+func setDirectQueryRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
+	err := validateConfig(d, meta)
+	if err != nil {
+		return err
+	}
+
+	client := meta.(*powerbiapi.Client)
+
+	datasetID, err := getDatasetID(d, meta)
+	if err != nil {
+		return err
+	}
+	groupID, err := getGroupID(d, meta)
+	if err != nil {
+		return err
+	}
+
+	// the API does not allow frequency to be sent along with days and times
+	requestVal := powerbiapi.UpdateDirectQueryRefreshScheduleInGroupRequestValue{
+		LocalTimeZoneID: convertStringToPointer(d.Get("local_time_zone_id").(string)),
+	}
+	if frequency, ok := d.GetOk("frequency"); ok {
+		frequency := frequency.(int)
+		requestVal.Frequency = &frequency
+	} else {
+		requestVal.Days = convertStringSliceToPointer(convertToStringSlice(d.Get("days").([]interface{})))
+		requestVal.Times = convertStringSliceToPointer(convertToStringSlice(d.Get("times").([]interface{})))
+	}
+
+	err = client.UpdateDirectQueryRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateDirectQueryRefreshScheduleInGroupRequest{
+		Value: requestVal,
+	})
+	if err != nil {
+		return err
+	}
+
+	return readDirectQueryRefreshSchedule(d, meta)
+}
+
+func createDirectQueryRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
+	return setDirectQueryRefreshSchedule(d, meta)
+}
+
+func readDirectQueryRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	datasetID, err := getDatasetID(d, meta)
+	if err != nil {
+		return err
+	}
+	groupID, err := getGroupID(d, meta)
+	if err != nil {
+		return err
+	}
+
+	refreshSchedule, err := client.GetDirectQueryRefreshScheduleInGroup(groupID, datasetID)
+	if isHTTP404Error(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(datasetID)
+	d.Set("dataset_id", datasetID)
+	d.Set("workspace_id", groupID)
+	d.Set("frequency", refreshSchedule.Frequency)
+	d.Set("days", refreshSchedule.Days)
+	d.Set("times", refreshSchedule.Times)
+	d.Set("local_time_zone_id", refreshSchedule.LocalTimeZoneID)
+
+	return nil
+}
+
+func updateDirectQueryRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
+	return setDirectQueryRefreshSchedule(d, meta)
+}
+
+func deleteDirectQueryRefreshSchedule(d *schema.ResourceData, meta interface{}) error {
+	// DirectQuery refresh schedules can neither be deleted nor disabled, so the schedule is left as is
+	return nil
+}
@@ -0,0 +1,168 @@
+package powerbi
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestUnitDirectQueryRefreshSchedule_basic(t *testing.T) {
+	var datasetID string
+	var groupID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+
+	resource "powerbi_direct_query_refresh_schedule" "test" {
+		dataset_id = "${powerbi_pbix.test.dataset_id}"
+		workspace_id = "${powerbi_pbix.test.workspace_id}"
+		local_time_zone_id = "Pacific Standard Time"
+		%s
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, `frequency = 30`),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_direct_query_refresh_schedule.test", "dataset_id", &datasetID),
+					set("powerbi_direct_query_refresh_schedule.test", "workspace_id", &groupID),
+					testCheckDirectQueryRefreshSchedule("powerbi_direct_query_refresh_schedule.test", powerbiapi.GetDirectQueryRefreshScheduleInGroupResponse{
+						Frequency:       30,
+						Days:            []string{},
+						Times:           []string{},
+						LocalTimeZoneID: "Pacific Standard Time",
+					}),
+				),
+			},
+			// skew is corrected
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					frequency := 120
+					client.UpdateDirectQueryRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateDirectQueryRefreshScheduleInGroupRequest{
+						Value: powerbiapi.UpdateDirectQueryRefreshScheduleInGroupRequestValue{
+							Frequency: &frequency,
+						},
+					})
+				},
+				Config: fmt.Sprintf(configTemplate, `frequency = 30`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_direct_query_refresh_schedule.test", "frequency", "30"),
+				),
+			},
+			// switching from a frequency to days and times
+			{
+				Config: fmt.Sprintf(configTemplate, `
+				days = ["Monday", "Friday"]
+				times = ["08:00", "12:30"]
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckDirectQueryRefreshSchedule("powerbi_direct_query_refresh_schedule.test", powerbiapi.GetDirectQueryRefreshScheduleInGroupResponse{
+						Days:            []string{"Monday", "Friday"},
+						Times:           []string{"08:00", "12:30"},
+						LocalTimeZoneID: "Pacific Standard Time",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitDirectQueryRefreshSchedule_validation(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_direct_query_refresh_schedule" "test" {
+					dataset_id = "validation-should-fail-before-using-this"
+					workspace_id = "validation-should-fail-before-using-this"
+					frequency = 45
+				}
+				`,
+				ExpectError: regexp.MustCompile("config is invalid:.*frequency.*"),
+			},
+			{
+				Config: `
+				resource "powerbi_direct_query_refresh_schedule" "test" {
+					dataset_id = "validation-should-fail-before-using-this"
+					workspace_id = "validation-should-fail-before-using-this"
+					frequency = 15
+					days = ["Monday"]
+					times = ["09:00"]
+				}
+				`,
+				ExpectError: regexp.MustCompile("only one of `days,frequency` can be specified"),
+			},
+			{
+				Config: `
+				resource "powerbi_direct_query_refresh_schedule" "test" {
+					dataset_id = "validation-should-fail-before-using-this"
+					workspace_id = "validation-should-fail-before-using-this"
+					days = ["Monday", "Badday"]
+					times = ["09:00"]
+				}
+				`,
+				ExpectError: regexp.MustCompile("config is invalid:.*days.*"),
+			},
+			{
+				Config: `
+				resource "powerbi_direct_query_refresh_schedule" "test" {
+					dataset_id = "validation-should-fail-before-using-this"
+					workspace_id = "validation-should-fail-before-using-this"
+					days = ["Monday"]
+					times = ["09:45"]
+				}
+				`,
+				ExpectError: regexp.MustCompile("config is invalid:.*times.*"),
+			},
+		},
+	})
+}
+
+func testCheckDirectQueryRefreshSchedule(scheduleRefreshResourceName string, expectedRefreshSchedule powerbiapi.GetDirectQueryRefreshScheduleInGroupResponse) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		datasetID, err := getResourceProperty(s, scheduleRefreshResourceName, "dataset_id")
+		if err != nil {
+			return err
+		}
+		groupID, err := getResourceProperty(s, scheduleRefreshResourceName, "workspace_id")
+		if err != nil {
+			return err
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		actualRefreshSchedule, err := client.GetDirectQueryRefreshScheduleInGroup(groupID, datasetID)
+		if err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(expectedRefreshSchedule, *actualRefreshSchedule) {
+			return fmt.Errorf("Expected refresh schedule %v. Found refresh schedule %v", expectedRefreshSchedule, actualRefreshSchedule)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,172 @@
+package powerbi
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// ResourceGatewayDatasource represents a datasource configured on a Power BI gateway
+func ResourceGatewayDatasource() *schema.Resource {
+	resourceSchema := credentialsSchema()
+	resourceSchema["gateway_id"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The ID of the gateway to create the datasource on.",
+		Required:    true,
+		ForceNew:    true,
+	}
+	resourceSchema["datasource_name"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The name of the datasource. Must be unique within the gateway.",
+		Required:    true,
+		ForceNew:    true,
+	}
+	resourceSchema["datasource_type"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Description: "The type of the datasource, for example `Sql`, `AnalysisServices`, `File`, `Web` or `OData`.",
+		Required:    true,
+		ForceNew:    true,
+	}
+	resourceSchema["connection_details"] = &schema.Schema{
+		Type: schema.TypeMap,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+		Description: "The connection details of the datasource, for example `server` and `database` for a `Sql` datasource or `url` for a `Web` datasource.",
+		Required:    true,
+		ForceNew:    true,
+	}
+	resourceSchema["encrypted_credentials"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Description:   "The credentials of the datasource, already encrypted with the public key of the gateway. Can be used instead of `username`, `password`, `key` or `access_token` to keep plain text credentials out of the configuration.",
+		Optional:      true,
+		Sensitive:     true,
+		ConflictsWith: []string{"username", "password", "key", "access_token"},
+	}
+
+	return &schema.Resource{
+		Create: createGatewayDatasource,
+		Read:   readGatewayDatasource,
+		Update: updateGatewayDatasource,
+		Delete: deleteGatewayDatasource,
+
+		Schema: resourceSchema,
+	}
+}
+
+func createGatewayDatasource(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	credentials, encryptionAlgorithm, err := getGatewayDatasourceCredentials(d, meta)
+	if err != nil {
+		return err
+	}
+
+	connectionDetails, err := json.Marshal(d.Get("connection_details").(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.CreateGatewayDatasource(d.Get("gateway_id").(string), powerbiapi.CreateGatewayDatasourceRequest{
+		DataSourceType:    d.Get("datasource_type").(string),
+		ConnectionDetails: string(connectionDetails),
+		DatasourceName:    d.Get("datasource_name").(string),
+		CredentialDetails: powerbiapi.CreateGatewayDatasourceRequestCredentialDetails{
+			CredentialType:      d.Get("credential_type").(string),
+			Credentials:         credentials,
+			EncryptedConnection: d.Get("encrypted_connection").(string),
+			EncryptionAlgorithm: encryptionAlgorithm,
+			PrivacyLevel:        d.Get("privacy_level").(string),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resp.ID)
+
+	return readGatewayDatasource(d, meta)
+}
+
+func readGatewayDatasource(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	datasource, err := client.GetGatewayDatasource(d.Get("gateway_id").(string), d.Id())
+	if isHTTP404Error(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var connectionDetails map[string]string
+	if err := json.Unmarshal([]byte(datasource.ConnectionDetails), &connectionDetails); err != nil {
+		return fmt.Errorf("Could not read connection details of gateway datasource %s: %s", d.Id(), err)
+	}
+
+	d.Set("gateway_id", datasource.GatewayID)
+	d.Set("datasource_name", datasource.DatasourceName)
+	d.Set("datasource_type", datasource.DatasourceType)
+	d.Set("connection_details", connectionDetails)
+	d.Set("credential_type", datasource.CredentialType)
+
+	return nil
+}
+
+func updateGatewayDatasource(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	if d.HasChanges("credential_type", "username", "password", "key", "access_token", "encrypted_credentials", "encrypted_connection", "privacy_level") {
+		credentials, encryptionAlgorithm, err := getGatewayDatasourceCredentials(d, meta)
+		if err != nil {
+			return err
+		}
+
+		err = client.UpdateGatewayDatasource(d.Get("gateway_id").(string), d.Id(), powerbiapi.UpdateGatewayDatasourceRequest{
+			CredentialDetails: powerbiapi.UpdateGatewayDatasourceRequestCredentialDetails{
+				CredentialType:      d.Get("credential_type").(string),
+				Credentials:         credentials,
+				EncryptedConnection: d.Get("encrypted_connection").(string),
+				EncryptionAlgorithm: encryptionAlgorithm,
+				PrivacyLevel:        d.Get("privacy_level").(string),
+			},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return readGatewayDatasource(d, meta)
+}
+
+func deleteGatewayDatasource(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	return client.DeleteGatewayDatasource(d.Get("gateway_id").(string), d.Id())
+}
+
+// getGatewayDatasourceCredentials returns the credentials to send to the service along with the algorithm they are encrypted with
+func getGatewayDatasourceCredentials(d *schema.ResourceData, meta interface{}) (string, string, error) {
+	client := meta.(*powerbiapi.Client)
+
+	credentialType := d.Get("credential_type").(string)
+	if encryptedCredentials := d.Get("encrypted_credentials").(string); encryptedCredentials != "" {
+		return encryptedCredentials, "RSA-OAEP", nil
+	}
+
+	credentials, err := powerbiapi.Credentials{
+		CredentialType: credentialType,
+		Username:       d.Get("username").(string),
+		Password:       d.Get("password").(string),
+		Key:            d.Get("key").(string),
+		AccessToken:    d.Get("access_token").(string),
+	}.JSON()
+	if err != nil {
+		return "", "", err
+	}
+
+	return encryptCredentialsForGateway(client, d.Get("gateway_id").(string), credentialType, credentials)
+}
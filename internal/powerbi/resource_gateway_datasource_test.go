@@ -0,0 +1,240 @@
+package powerbi
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestUnitGatewayDatasource_basic(t *testing.T) {
+	var datasourceID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	gateway := server.AddGateway(powerbiapitest.Gateway{Name: "Unit Test Gateway"})
+	exponent, modulus := gateway.PublicKey()
+	basicCredentials := `{"credentialData":[{"name":"username","value":"user"},{"name":"password","value":"pass"}]}`
+	encryptedCredentials, err := powerbiapi.EncryptCredentials(basicCredentials, exponent, modulus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	configTemplate := `
+	resource "powerbi_gateway_datasource" "test" {
+		gateway_id = "%s"
+		datasource_name = "Unit Test Datasource"
+		datasource_type = "Web"
+		connection_details = {
+			url = "%s"
+		}
+		%s
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckGatewayDatasourcesDestroyed(gateway.ID),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, "https://example.com/data", `credential_type = "Anonymous"`),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_gateway_datasource.test", "id", &datasourceID),
+					resource.TestCheckResourceAttr("powerbi_gateway_datasource.test", "connection_details.url", "https://example.com/data"),
+					testCheckGatewayDatasourceCredentialType("powerbi_gateway_datasource.test", "Anonymous"),
+				),
+			},
+			// credentials are updated in place
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, "https://example.com/data", `
+				credential_type = "Basic"
+				encrypted_credentials = "`+encryptedCredentials+`"
+				privacy_level = "Organizational"
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_gateway_datasource.test", "id", &datasourceID),
+					testCheckGatewayDatasourceCredentialType("powerbi_gateway_datasource.test", "Basic"),
+					testCheckGatewayDatasourceCredentials(server, "powerbi_gateway_datasource.test", basicCredentials),
+				),
+			},
+			// plain text credentials are encrypted with the gateway public key
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, "https://example.com/data", `
+				credential_type = "Key"
+				key = "secret-key"
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_gateway_datasource.test", "id", &datasourceID),
+					testCheckGatewayDatasourceCredentialType("powerbi_gateway_datasource.test", "Key"),
+					testCheckGatewayDatasourceCredentials(server, "powerbi_gateway_datasource.test", `{"credentialData":[{"name":"key","value":"secret-key"}]}`),
+				),
+			},
+			// changed connection details recreate the datasource
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, "https://example.com/other", `
+				credential_type = "Basic"
+				encrypted_credentials = "`+encryptedCredentials+`"
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceAttrChanged("powerbi_gateway_datasource.test", "id", &datasourceID),
+					resource.TestCheckResourceAttr("powerbi_gateway_datasource.test", "connection_details.url", "https://example.com/other"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitGatewayDatasource_incompleteCredentials(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	gateway := server.AddGateway(powerbiapitest.Gateway{Name: "Unit Test Gateway"})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "powerbi_gateway_datasource" "test" {
+					gateway_id = "%s"
+					datasource_name = "Unit Test Datasource"
+					datasource_type = "Sql"
+					connection_details = {
+						server = "sql.example.com"
+						database = "sales"
+					}
+					credential_type = "Windows"
+				}
+				`, gateway.ID),
+				ExpectError: regexp.MustCompile("username and password are required for Windows credentials"),
+			},
+		},
+	})
+}
+
+func TestUnitGatewayDatasourceUser_basic(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	gateway := server.AddGateway(powerbiapitest.Gateway{
+		Name: "Unit Test Gateway",
+		Datasources: []powerbiapitest.GatewayDatasource{
+			{DatasourceType: "Sql", DatasourceName: "Sales", ConnectionDetails: map[string]string{"server": "sql.example.com", "database": "sales"}},
+		},
+	})
+	datasourceID := gateway.Datasources[0].ID
+
+	configTemplate := `
+	resource "powerbi_gateway_datasource_user" "user" {
+		gateway_id = "%s"
+		datasource_id = "%s"
+		email_address = "user@example.com"
+		datasource_access_right = "%s"
+	}
+
+	resource "powerbi_gateway_datasource_user" "group" {
+		gateway_id = "%s"
+		datasource_id = "%s"
+		identifier = "f3a7c6b1-6a2d-4e8b-9e1f-2d4c5b6a7e8f"
+		principal_type = "Group"
+		datasource_access_right = "Read"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testCheckGatewayDatasourceUsers(gateway.ID, datasourceID, map[string]string{}),
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, datasourceID, "Read", gateway.ID, datasourceID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_gateway_datasource_user.user", "identifier", "user@example.com"),
+					testCheckGatewayDatasourceUsers(gateway.ID, datasourceID, map[string]string{
+						"user@example.com":                     "Read",
+						"f3a7c6b1-6a2d-4e8b-9e1f-2d4c5b6a7e8f": "Read",
+					}),
+				),
+			},
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, datasourceID, "ReadOverrideEffectiveIdentity", gateway.ID, datasourceID),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckGatewayDatasourceUsers(gateway.ID, datasourceID, map[string]string{
+						"user@example.com":                     "ReadOverrideEffectiveIdentity",
+						"f3a7c6b1-6a2d-4e8b-9e1f-2d4c5b6a7e8f": "Read",
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testCheckGatewayDatasourceCredentialType(resourceName string, expectedCredentialType string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		datasource, err := client.GetGatewayDatasource(rs.Primary.Attributes["gateway_id"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if datasource.CredentialType != expectedCredentialType {
+			return fmt.Errorf("Expecting gateway datasource credential type '%s'. Found '%s'", expectedCredentialType, datasource.CredentialType)
+		}
+		return nil
+	}
+}
+
+func testCheckGatewayDatasourceCredentials(server *powerbiapitest.Server, resourceName string, expectedCredentials string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", resourceName)
+		}
+
+		actualCredentials := server.DatasourceCredentials(rs.Primary.Attributes["gateway_id"], rs.Primary.Attributes["datasource_id"])
+		if rs.Primary.Attributes["datasource_id"] == "" {
+			actualCredentials = server.DatasourceCredentials(rs.Primary.Attributes["gateway_id"], rs.Primary.ID)
+		}
+		if actualCredentials != expectedCredentials {
+			return fmt.Errorf("Expecting datasource credentials %s. Found %s", expectedCredentials, actualCredentials)
+		}
+		return nil
+	}
+}
+
+func testCheckGatewayDatasourcesDestroyed(gatewayID string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		datasources, err := client.GetGatewayDatasources(gatewayID)
+		if err != nil {
+			return err
+		}
+		if len(datasources.Value) != 0 {
+			return fmt.Errorf("Expecting no datasources on gateway %s. Found %v", gatewayID, datasources.Value)
+		}
+		return nil
+	}
+}
+
+func testCheckGatewayDatasourceUsers(gatewayID string, datasourceID string, expectedAccessRights map[string]string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		users, err := client.GetGatewayDatasourceUsers(gatewayID, datasourceID)
+		if err != nil {
+			return err
+		}
+
+		actualAccessRights := map[string]string{}
+		for _, user := range users.Value {
+			actualAccessRights[user.Identifier] = user.DatasourceAccessRight
+		}
+		if fmt.Sprint(actualAccessRights) != fmt.Sprint(expectedAccessRights) {
+			return fmt.Errorf("Expecting datasource users %v. Found %v", expectedAccessRights, actualAccessRights)
+		}
+		return nil
+	}
+}
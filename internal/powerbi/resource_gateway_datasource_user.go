@@ -0,0 +1,136 @@
+package powerbi
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// ResourceGatewayDatasourceUser represents a user's access to a datasource on a Power BI gateway
+func ResourceGatewayDatasourceUser() *schema.Resource {
+	return &schema.Resource{
+		Create: setGatewayDatasourceUser,
+		Read:   readGatewayDatasourceUser,
+		Update: setGatewayDatasourceUser,
+		Delete: deleteGatewayDatasourceUser,
+
+		Schema: map[string]*schema.Schema{
+			"gateway_id": {
+				Type:        schema.TypeString,
+				Description: "The ID of the gateway the datasource is on.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"datasource_id": {
+				Type:        schema.TypeString,
+				Description: "The ID of the gateway datasource to give access to.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"datasource_access_right": {
+				Type:         schema.TypeString,
+				Description:  "The access right the user has on the datasource. Either `Read` or `ReadOverrideEffectiveIdentity`.",
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Read", "ReadOverrideEffectiveIdentity"}, false),
+			},
+			"email_address": {
+				Type:         schema.TypeString,
+				Description:  "Email address of the user. Exactly one of `email_address` or `identifier` must be provided.",
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"email_address", "identifier"},
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(".*@.*"), "must be an email address"),
+			},
+			"identifier": {
+				Type:         schema.TypeString,
+				Description:  "Identifier of the principal, such as the object ID of a group or service principal. Exactly one of `email_address` or `identifier` must be provided.",
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"email_address", "identifier"},
+			},
+			"principal_type": {
+				Type:         schema.TypeString,
+				Description:  "The principal type. Any value from `App`, `Group` or `User`.",
+				Optional:     true,
+				ForceNew:     true,
+				Default:      "User",
+				ValidateFunc: validation.StringInSlice([]string{"User", "App", "Group"}, false),
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Description: "Display name of the principal.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func setGatewayDatasourceUser(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	gatewayID := d.Get("gateway_id").(string)
+	datasourceID := d.Get("datasource_id").(string)
+
+	err := client.AddGatewayDatasourceUser(gatewayID, datasourceID, powerbiapi.AddGatewayDatasourceUserRequest{
+		DatasourceAccessRight: d.Get("datasource_access_right").(string),
+		EmailAddress:          d.Get("email_address").(string),
+		Identifier:            d.Get("identifier").(string),
+		PrincipalType:         d.Get("principal_type").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", gatewayID, datasourceID, getGatewayDatasourceUserInfo(d)))
+
+	return readGatewayDatasourceUser(d, meta)
+}
+
+func readGatewayDatasourceUser(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	users, err := client.GetGatewayDatasourceUsers(d.Get("gateway_id").(string), d.Get("datasource_id").(string))
+	if isHTTP404Error(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	userInfo := getGatewayDatasourceUserInfo(d)
+	for _, user := range users.Value {
+		if strings.EqualFold(user.Identifier, userInfo) || (user.EmailAddress != "" && strings.EqualFold(user.EmailAddress, userInfo)) {
+			d.Set("datasource_access_right", user.DatasourceAccessRight)
+			d.Set("identifier", user.Identifier)
+			d.Set("display_name", user.DisplayName)
+			if user.PrincipalType != "" {
+				d.Set("principal_type", user.PrincipalType)
+			}
+			return nil
+		}
+	}
+
+	// the user no longer has access to the datasource
+	d.SetId("")
+	return nil
+}
+
+func deleteGatewayDatasourceUser(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	return client.DeleteGatewayDatasourceUser(d.Get("gateway_id").(string), d.Get("datasource_id").(string), getGatewayDatasourceUserInfo(d))
+}
+
+// getGatewayDatasourceUserInfo returns the value the service identifies the user by, users by email address and other principals by identifier
+func getGatewayDatasourceUserInfo(d *schema.ResourceData) string {
+	if emailAddress := d.Get("email_address").(string); emailAddress != "" {
+		return emailAddress
+	}
+	return d.Get("identifier").(string)
+}
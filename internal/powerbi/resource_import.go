@@ -0,0 +1,468 @@
+package powerbi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// importTypeExtensions are the file extensions of each type of import
+var importTypeExtensions = map[string]string{
+	".pbix": "pbix",
+	".xlsx": "xlsx",
+	".rdl":  "rdl",
+	".json": "dataflow",
+}
+
+// importNameConflicts are the nameConflict values supported by each type of import, the first being the default
+var importNameConflicts = map[string][]string{
+	"pbix":     {"CreateOrOverwrite", "Abort", "Ignore", "Overwrite"},
+	"xlsx":     {"CreateOrOverwrite", "Abort", "Ignore", "Overwrite"},
+	"rdl":      {"Abort", "Overwrite"},
+	"dataflow": {"Abort", "GenerateUniqueName"},
+}
+
+// importReuploadNameConflicts are the nameConflict values used when reuploading to replace the imported content
+var importReuploadNameConflicts = map[string]string{
+	"pbix": "CreateOrOverwrite",
+	"xlsx": "CreateOrOverwrite",
+	"rdl":  "Overwrite",
+}
+
+// ResourceImport represents a file imported into Power BI, such as an Excel workbook, paginated report or dataflow
+func ResourceImport() *schema.Resource {
+	return &schema.Resource{
+		Create: createImportFile,
+		Read:   readImportFile,
+		Update: updateImportFile,
+		Delete: deleteImportFile,
+
+		CustomizeDiff: customizeImportFileDiff,
+
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Description: "Workspace ID in which the file will be imported.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"source": {
+				Type:        schema.TypeString,
+				Description: "An absolute path to the file on the local system. The type of import is determined by the file extension, either `.pbix`, `.xlsx`, `.rdl` or `.json` for a dataflow `model.json`.",
+				Required:    true,
+			},
+			"source_hash": {
+				Type:        schema.TypeString,
+				Description: "Used to trigger updates. The only meaningful value is `${filemd5(\"path/to/file\")}`.",
+				Optional:    true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the import. This will be used as the name for the report and dataset. Required for all imports except dataflows, which are named by their `model.json`. The name of an `.rdl` import must end with `.rdl`.",
+				Optional:    true,
+				ForceNew:    true,
+			},
+			"name_conflict": {
+				Type:         schema.TypeString,
+				Description:  "What to do if content with the same name already exists when the file is first imported. `.pbix` and `.xlsx` imports support `Abort`, `Ignore`, `Overwrite` and `CreateOrOverwrite` (default). `.rdl` imports support `Abort` (default) and `Overwrite`. Dataflow imports support `Abort` (default) and `GenerateUniqueName`. Reuploads always overwrite the content previously imported.",
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Abort", "Ignore", "Overwrite", "CreateOrOverwrite", "GenerateUniqueName"}, false),
+			},
+			"skip_report": {
+				Type:        schema.TypeBool,
+				Description: "If true, only the dataset of a `.pbix` import is deployed.",
+				Optional:    true,
+				ForceNew:    true,
+				Default:     false,
+			},
+			"override_report_label": {
+				Type:        schema.TypeBool,
+				Description: "If true, the sensitivity label of an existing report is overwritten when a `.pbix` is imported.",
+				Optional:    true,
+				Default:     false,
+			},
+			"override_model_label": {
+				Type:        schema.TypeBool,
+				Description: "If true, the sensitivity label of an existing dataset is overwritten when a `.pbix` is imported.",
+				Optional:    true,
+				Default:     false,
+			},
+			"type": {
+				Type:        schema.TypeString,
+				Description: "The type of import, determined by the file extension of `source`. Either `pbix`, `xlsx`, `rdl` or `dataflow`.",
+				Computed:    true,
+			},
+			"reports": {
+				Type:        schema.TypeList,
+				Description: "The reports created by the import.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "The ID of the report",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the report",
+							Computed:    true,
+						},
+						"report_type": {
+							Type:        schema.TypeString,
+							Description: "The type of report. Either `PowerBIReport` or `PaginatedReport`",
+							Computed:    true,
+						},
+						"web_url": {
+							Type:        schema.TypeString,
+							Description: "The web URL of the report",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"datasets": {
+				Type:        schema.TypeList,
+				Description: "The datasets created by the import.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Description: "The ID of the dataset",
+							Computed:    true,
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Description: "The name of the dataset",
+							Computed:    true,
+						},
+						"target_storage_mode": {
+							Type:        schema.TypeString,
+							Description: "The storage mode of the dataset",
+							Computed:    true,
+						},
+						"web_url": {
+							Type:        schema.TypeString,
+							Description: "The web URL of the dataset",
+							Computed:    true,
+						},
+					},
+				},
+			},
+			"dataflow_id": {
+				Type:        schema.TypeString,
+				Description: "The ID of the dataflow created by a dataflow import.",
+				Computed:    true,
+			},
+			"dataflow_name": {
+				Type:        schema.TypeString,
+				Description: "The name of the dataflow created by a dataflow import.",
+				Computed:    true,
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Default: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func importTypeFromSource(source string) (string, error) {
+	extension := strings.ToLower(filepath.Ext(source))
+	importType, ok := importTypeExtensions[extension]
+	if !ok {
+		return "", fmt.Errorf("Unable to import %s. Only .pbix, .xlsx, .rdl and dataflow .json files can be imported", source)
+	}
+	return importType, nil
+}
+
+func customizeImportFileDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("source") {
+		return nil
+	}
+
+	importType, err := importTypeFromSource(d.Get("source").(string))
+	if err != nil {
+		return err
+	}
+	if importType != d.Get("type").(string) {
+		d.SetNew("type", importType)
+		if d.Id() != "" {
+			d.ForceNew("type")
+		}
+	}
+
+	if d.NewValueKnown("name") {
+		name := d.Get("name").(string)
+		switch {
+		case importType == "dataflow" && name != "":
+			return fmt.Errorf("name cannot be set for dataflow imports, the dataflow is named by its model.json")
+		case importType != "dataflow" && name == "":
+			return fmt.Errorf("name is required for %s imports", importType)
+		case importType == "rdl" && !strings.HasSuffix(strings.ToLower(name), ".rdl"):
+			return fmt.Errorf("name of an rdl import must end with .rdl")
+		}
+	}
+
+	if nameConflict := d.Get("name_conflict").(string); nameConflict != "" && !isImportNameConflictSupported(importType, nameConflict) {
+		return fmt.Errorf("name_conflict %s is not supported for %s imports. Supported values are %s", nameConflict, importType, strings.Join(importNameConflicts[importType], ", "))
+	}
+
+	if importType != "pbix" {
+		for _, key := range []string{"skip_report", "override_report_label", "override_model_label"} {
+			if d.Get(key).(bool) {
+				return fmt.Errorf("%s is only supported for pbix imports", key)
+			}
+		}
+	}
+
+	// dataflows cannot be overwritten by an import so changing their content requires a new dataflow
+	if importType == "dataflow" && d.Id() != "" {
+		for _, key := range []string{"source", "source_hash"} {
+			if d.HasChange(key) {
+				d.ForceNew(key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isImportNameConflictSupported(importType string, nameConflict string) bool {
+	for _, supported := range importNameConflicts[importType] {
+		if supported == nameConflict {
+			return true
+		}
+	}
+	return false
+}
+
+func createImportFile(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+	groupID := d.Get("workspace_id").(string)
+
+	importType, err := importTypeFromSource(d.Get("source").(string))
+	if err != nil {
+		return err
+	}
+	d.Set("type", importType)
+
+	nameConflict := d.Get("name_conflict").(string)
+	if nameConflict == "" {
+		nameConflict = importNameConflicts[importType][0]
+	}
+
+	// imports do not return the dataflows they create, so dataflows that already exist are noted to find the new one
+	existingDataflowIDs := map[string]bool{}
+	if importType == "dataflow" {
+		dataflows, err := client.GetDataflowsInGroup(groupID)
+		if err != nil {
+			return err
+		}
+		for _, dataflow := range dataflows.Value {
+			existingDataflowIDs[dataflow.ObjectID] = true
+		}
+	}
+
+	err = postImportFile(d, meta, nameConflict)
+	if err != nil {
+		return err
+	}
+
+	im, err := client.WaitForImportInGroupToSucceed(groupID, d.Id(), d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	if importType == "dataflow" {
+		err = findImportedDataflow(d, meta, existingDataflowIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return setImportFileArtifacts(d, im)
+}
+
+func readImportFile(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+	groupID := d.Get("workspace_id").(string)
+
+	im, err := client.GetImportInGroup(groupID, d.Id())
+	if isHTTP404Error(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if d.Get("type").(string) == "dataflow" {
+		dataflows, err := client.GetDataflowsInGroup(groupID)
+		if err != nil {
+			return err
+		}
+
+		dataflowID := d.Get("dataflow_id").(string)
+		var found *powerbiapi.GetDataflowsInGroupResponseItem
+		for _, dataflow := range dataflows.Value {
+			if strings.EqualFold(dataflow.ObjectID, dataflowID) {
+				found = &dataflow
+				break
+			}
+		}
+		if found == nil {
+			d.SetId("")
+			return nil
+		}
+		d.Set("dataflow_name", found.Name)
+	}
+
+	return setImportFileArtifacts(d, im)
+}
+
+func updateImportFile(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	if d.HasChange("source") || d.HasChange("source_hash") {
+		err := postImportFile(d, meta, importReuploadNameConflicts[d.Get("type").(string)])
+		if err != nil {
+			return err
+		}
+
+		im, err := client.WaitForImportInGroupToSucceed(d.Get("workspace_id").(string), d.Id(), d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return err
+		}
+
+		return setImportFileArtifacts(d, im)
+	}
+
+	return nil
+}
+
+func deleteImportFile(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+	groupID := d.Get("workspace_id").(string)
+
+	// reports are deleted before the datasets they use
+	for _, reportObj := range d.Get("reports").([]interface{}) {
+		err := client.DeleteReportInGroup(groupID, reportObj.(map[string]interface{})["id"].(string))
+		if err != nil && !isHTTP404Error(err) {
+			return err
+		}
+	}
+
+	for _, datasetObj := range d.Get("datasets").([]interface{}) {
+		err := client.DeleteDatasetInGroup(groupID, datasetObj.(map[string]interface{})["id"].(string))
+		if err != nil && !isHTTP404Error(err) {
+			return err
+		}
+	}
+
+	if dataflowID, dataflowIDOk := d.GetOk("dataflow_id"); dataflowIDOk {
+		err := client.DeleteDataflowInGroup(groupID, dataflowID.(string))
+		if err != nil && !isHTTP404Error(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func postImportFile(d *schema.ResourceData, meta interface{}, nameConflict string) error {
+	client := meta.(*powerbiapi.Client)
+
+	reader, err := os.Open(d.Get("source").(string))
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	displayName := d.Get("name").(string)
+	if d.Get("type").(string) == "dataflow" {
+		displayName = "model.json"
+	}
+
+	resp, err := client.PostImportInGroup(
+		d.Get("workspace_id").(string),
+		powerbiapi.PostImportInGroupRequest{
+			DatasetDisplayName:  displayName,
+			NameConflict:        nameConflict,
+			SkipReport:          d.Get("skip_report").(bool),
+			OverrideReportLabel: d.Get("override_report_label").(bool),
+			OverrideModelLabel:  d.Get("override_model_label").(bool),
+		},
+		reader,
+	)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resp.ID)
+	return nil
+}
+
+// findImportedDataflow finds the dataflow created by the import, which is named by the model.json
+// unless a unique name had to be generated
+func findImportedDataflow(d *schema.ResourceData, meta interface{}, existingDataflowIDs map[string]bool) error {
+	client := meta.(*powerbiapi.Client)
+
+	modelJSON, err := ioutil.ReadFile(d.Get("source").(string))
+	if err != nil {
+		return err
+	}
+	var model struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(modelJSON, &model); err != nil {
+		return fmt.Errorf("Unable to read dataflow name from %s: %s", d.Get("source").(string), err)
+	}
+
+	dataflows, err := client.GetDataflowsInGroup(d.Get("workspace_id").(string))
+	if err != nil {
+		return err
+	}
+	for _, dataflow := range dataflows.Value {
+		if !existingDataflowIDs[dataflow.ObjectID] && strings.HasPrefix(strings.ToLower(dataflow.Name), strings.ToLower(model.Name)) {
+			d.Set("dataflow_id", dataflow.ObjectID)
+			d.Set("dataflow_name", dataflow.Name)
+			return nil
+		}
+	}
+	return fmt.Errorf("Unable to find the dataflow %s created by import %s", model.Name, d.Id())
+}
+
+func setImportFileArtifacts(d *schema.ResourceData, im *powerbiapi.GetImportInGroupResponse) error {
+	reports := []interface{}{}
+	for _, report := range im.Reports {
+		reports = append(reports, map[string]interface{}{
+			"id":          report.ID,
+			"name":        report.Name,
+			"report_type": report.ReportType,
+			"web_url":     report.WebURL,
+		})
+	}
+
+	datasets := []interface{}{}
+	for _, dataset := range im.Datasets {
+		datasets = append(datasets, map[string]interface{}{
+			"id":                  dataset.ID,
+			"name":                dataset.Name,
+			"target_storage_mode": dataset.TargetStorageMode,
+			"web_url":             dataset.WebURL,
+		})
+	}
+
+	d.Set("reports", reports)
+	d.Set("datasets", datasets)
+	return nil
+}
@@ -0,0 +1,202 @@
+package powerbi
+
+import (
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestUnitImport_paginated_report(t *testing.T) {
+	var reportID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+
+	rdlLocation := TempFileName("", ".rdl")
+	rdlLocationTfFriendly := strings.ReplaceAll(rdlLocation, "\\", "\\\\")
+	config := fmt.Sprintf(`
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_import" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test Report.rdl"
+		source = "%s"
+		source_hash = "${filemd5("%s")}"
+	}
+	`, rdlLocationTfFriendly, rdlLocationTfFriendly)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			// first step imports the paginated report
+			{
+				PreConfig: func() {
+					ioutil.WriteFile(rdlLocation, []byte("<Report>1</Report>"), 0644)
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckReportExistsInWorkspace("powerbi_workspace.test", "Unit Test Report"),
+					set("powerbi_import.test", "reports.0.id", &reportID),
+					resource.TestCheckResourceAttr("powerbi_import.test", "type", "rdl"),
+					resource.TestCheckResourceAttr("powerbi_import.test", "reports.#", "1"),
+					resource.TestCheckResourceAttr("powerbi_import.test", "reports.0.report_type", "PaginatedReport"),
+					resource.TestCheckResourceAttr("powerbi_import.test", "datasets.#", "0"),
+				),
+			},
+			// changing the file overwrites the same report
+			{
+				PreConfig: func() {
+					ioutil.WriteFile(rdlLocation, []byte("<Report>2</Report>"), 0644)
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_import.test", "reports.0.id", &reportID),
+					testCheckImportContent(server, "powerbi_import.test", "<Report>2</Report>"),
+				),
+			},
+			// deletes the resource
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckReportDoesNotExistsInWorkspace("powerbi_workspace.test", "Unit Test Report"),
+					testCheckResourceRemoved("powerbi_import.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitImport_dataflow(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	modelLocation := TempFileName("", ".json")
+	modelLocationTfFriendly := strings.ReplaceAll(modelLocation, "\\", "\\\\")
+	ioutil.WriteFile(modelLocation, []byte(`{"name": "Unit Test Dataflow", "version": "1.0", "entities": []}`), 0644)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			// both dataflows are imported, the second with a unique name
+			{
+				Config: fmt.Sprintf(`
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_import" "first" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					source = "%s"
+				}
+
+				resource "powerbi_import" "second" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					source = "%s"
+					name_conflict = "GenerateUniqueName"
+					depends_on = ["powerbi_import.first"]
+				}
+				`, modelLocationTfFriendly, modelLocationTfFriendly),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_import.first", "type", "dataflow"),
+					resource.TestCheckResourceAttrSet("powerbi_import.first", "dataflow_id"),
+					resource.TestCheckResourceAttr("powerbi_import.first", "dataflow_name", "Unit Test Dataflow"),
+					resource.TestCheckResourceAttrSet("powerbi_import.second", "dataflow_id"),
+					resource.TestCheckResourceAttr("powerbi_import.second", "dataflow_name", "Unit Test Dataflow (2)"),
+				),
+			},
+			// importing a dataflow that already exists aborts by default
+			{
+				Config: fmt.Sprintf(`
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_import" "first" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					source = "%s"
+				}
+
+				resource "powerbi_import" "second" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					source = "%s"
+					name_conflict = "GenerateUniqueName"
+					depends_on = ["powerbi_import.first"]
+				}
+
+				resource "powerbi_import" "third" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					source = "%s"
+					depends_on = ["powerbi_import.second"]
+				}
+				`, modelLocationTfFriendly, modelLocationTfFriendly, modelLocationTfFriendly),
+				ExpectError: regexp.MustCompile("Dataflow named 'Unit Test Dataflow' already exists"),
+			},
+		},
+	})
+}
+
+func TestUnitImport_validation(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	tests := []struct {
+		source      string
+		attributes  string
+		expectError string
+	}{
+		{"./resource_pbix_test_sample1.pbix", ``, "name is required for pbix imports"},
+		{"./report.rdl", `name = "Report"`, "name of an rdl import must end with .rdl"},
+		{"./report.rdl", `name = "Report.rdl"
+		name_conflict = "CreateOrOverwrite"`, "name_conflict CreateOrOverwrite is not supported for rdl imports"},
+		{"./model.json", `name = "Dataflow"`, "name cannot be set for dataflow imports"},
+		{"./workbook.xlsx", `name = "Workbook"
+		override_report_label = true`, "override_report_label is only supported for pbix imports"},
+		{"./report.docx", `name = "Report"`, "Only .pbix, .xlsx, .rdl and dataflow .json files can be imported"},
+	}
+
+	for _, test := range tests {
+		resource.UnitTest(t, resource.TestCase{
+			Providers: testAccProviders,
+			Steps: []resource.TestStep{
+				{
+					Config: fmt.Sprintf(`
+					resource "powerbi_import" "test" {
+						workspace_id = "workspace"
+						source = "%s"
+						%s
+					}
+					`, test.source, test.attributes),
+					PlanOnly:    true,
+					ExpectError: regexp.MustCompile(regexp.QuoteMeta(test.expectError)),
+				},
+			},
+		})
+	}
+}
+
+func testCheckImportContent(server *powerbiapitest.Server, importResourceName string, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		importID, err := getResourceID(s, importResourceName)
+		if err != nil {
+			return err
+		}
+
+		if actual := string(server.ImportContent(importID)); actual != expected {
+			return fmt.Errorf("Expected import content %s. Found %s", expected, actual)
+		}
+		return nil
+	}
+}
@@ -3,11 +3,15 @@ package powerbi
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/codecutout/terraform-provider-powerbi/internal/pbixrewriter"
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 // ResourcePBIX represents a Power BI PBIX file
@@ -21,6 +25,8 @@ func ResourcePBIX() *schema.Resource {
 			State: schema.ImportStatePassthrough,
 		},
 
+		CustomizeDiff: customizePBIXDiff,
+
 		Schema: map[string]*schema.Schema{
 			"workspace_id": {
 				Type:        schema.TypeString,
@@ -30,9 +36,8 @@ func ResourcePBIX() *schema.Resource {
 			},
 			"name": {
 				Type:        schema.TypeString,
-				Description: "Name of the PBIX. This will be used as the name for the report and dataset.",
+				Description: "Name of the PBIX. This will be used as the name for the report and dataset. Changing the name renames the report and dataset in place using the Fabric REST API, or replaces them in environments where the Fabric REST API is not available.",
 				Required:    true,
-				ForceNew:    true,
 			},
 			"source": {
 				Type:        schema.TypeString,
@@ -50,6 +55,45 @@ func ResourcePBIX() *schema.Resource {
 				Optional:    true,
 				Default:     false,
 			},
+			"name_conflict": {
+				Type:         schema.TypeString,
+				Description:  "What to do if a report or dataset with the same name already exists when the PBIX is first uploaded. Any value from `Abort`, `Overwrite`, `CreateOrOverwrite` or `GenerateUniqueName`. Reuploads always overwrite the report and dataset deployed by this resource.",
+				Optional:     true,
+				Default:      "CreateOrOverwrite",
+				ValidateFunc: validation.StringInSlice([]string{"Abort", "Overwrite", "CreateOrOverwrite", "GenerateUniqueName"}, false),
+			},
+			"adopt_existing": {
+				Type:        schema.TypeBool,
+				Description: "If true, a report or dataset with the same name that was not deployed by this resource is overwritten when the PBIX is uploaded with `CreateOrOverwrite` or `Overwrite`. Otherwise the upload fails if one exists.",
+				Optional:    true,
+				Default:     false,
+			},
+			"take_over": {
+				Type:        schema.TypeBool,
+				Description: "If true, ownership of the dataset is taken over by the deploying identity after the PBIX is uploaded. The dataset owner is the only identity that can bind the dataset to a gateway and refresh it with the stored credentials.",
+				Optional:    true,
+				Default:     false,
+			},
+			"gateway_id": {
+				Type:        schema.TypeString,
+				Description: "If set, the dataset is bound to the specified gateway after the PBIX is uploaded. The gateway must be one the dataset can be bound to.",
+				Optional:    true,
+			},
+			"gateway_datasource_ids": {
+				Type: schema.TypeSet,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Description:  "The IDs of the gateway datasources to bind the dataset's datasources to. If not set the gateway datasources are matched by connection details.",
+				Optional:     true,
+				RequiredWith: []string{"gateway_id"},
+			},
+			"refresh_dataset": {
+				Type:        schema.TypeBool,
+				Description: "If true, the dataset is refreshed after the PBIX is uploaded or its parameters change. The apply waits for the refresh to complete and fails if the refresh fails.",
+				Optional:    true,
+				Default:     false,
+			},
 			"report_id": {
 				Type:        schema.TypeString,
 				Description: "The ID for the report that was deployed as part of the PBIX.",
@@ -66,6 +110,26 @@ func ResourcePBIX() *schema.Resource {
 				Optional:      true,
 				ConflictsWith: []string{"parameter", "datasource"},
 			},
+			"deployed_name": {
+				Type:        schema.TypeString,
+				Description: "The name of the report and dataset in the workspace. This differs from `name` when `name_conflict` is `GenerateUniqueName` and a unique name was generated.",
+				Computed:    true,
+			},
+			"dataset_configured_by": {
+				Type:        schema.TypeString,
+				Description: "The owner of the dataset after it was deployed, or taken over if `take_over` is set.",
+				Computed:    true,
+			},
+			"import_updated_time": {
+				Type:        schema.TypeString,
+				Description: "The time the PBIX was last uploaded by this resource. Used to detect the report or dataset being republished outside of Terraform.",
+				Computed:    true,
+			},
+			"republished_import_id": {
+				Type:        schema.TypeString,
+				Description: "The ID of an import that republished the report or dataset outside of Terraform since the PBIX was last uploaded, for example from Power BI Desktop. The PBIX is reuploaded on the next apply to revert the republish.",
+				Computed:    true,
+			},
 			"report_original_dataset_id": {
 				Type:        schema.TypeString,
 				Description: "The dataset to which the report that was deployed is pointing. This is primarily used to allow reverting rebinded datasets back to the original source.",
@@ -92,7 +156,7 @@ func ResourcePBIX() *schema.Resource {
 			},
 			"datasource": {
 				Type:        schema.TypeSet,
-				Description: "Datasources to be reconfigured after deploying the PBIX dataset. Changing this value will require reuploading the PBIX. Any datasource updated will not be tracked",
+				Description: "Datasources to be reconfigured after deploying the PBIX dataset. Changing this value, including credentials, will require reuploading the PBIX. Datasources updated are tracked by datasource ID, so changes to their connection details outside of Terraform are detected",
 				Optional:    true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -116,6 +180,46 @@ func ResourcePBIX() *schema.Resource {
 							Description: "The service URL, if applicable for the type of datasource",
 							Optional:    true,
 						},
+						"path": {
+							Type:        schema.TypeString,
+							Description: "The path, if applicable for the type of datasource. For example the file path of a File datasource or the path of an Extension datasource",
+							Optional:    true,
+						},
+						"kind": {
+							Type:        schema.TypeString,
+							Description: "The kind of an Extension datasource. For example SharePointList",
+							Optional:    true,
+						},
+						"domain": {
+							Type:        schema.TypeString,
+							Description: "The domain, if applicable for the type of datasource",
+							Optional:    true,
+						},
+						"email_address": {
+							Type:        schema.TypeString,
+							Description: "The email address, if applicable for the type of datasource",
+							Optional:    true,
+						},
+						"account": {
+							Type:        schema.TypeString,
+							Description: "The account, if applicable for the type of datasource",
+							Optional:    true,
+						},
+						"class_info": {
+							Type:        schema.TypeString,
+							Description: "The class info, if applicable for the type of datasource",
+							Optional:    true,
+						},
+						"login_server": {
+							Type:        schema.TypeString,
+							Description: "The login server, if applicable for the type of datasource",
+							Optional:    true,
+						},
+						"connection_string": {
+							Type:        schema.TypeString,
+							Description: "The connection string, if applicable for the type of datasource",
+							Optional:    true,
+						},
 						"original_database": {
 							Type:        schema.TypeString,
 							Description: "The database name as configured in the PBIX, if applicable for the type of datasource This will be the value replaced with the value in the 'databsase' field",
@@ -131,6 +235,188 @@ func ResourcePBIX() *schema.Resource {
 							Description: "The service URL as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'url' field",
 							Optional:    true,
 						},
+						"original_path": {
+							Type:        schema.TypeString,
+							Description: "The path as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'path' field",
+							Optional:    true,
+						},
+						"original_kind": {
+							Type:        schema.TypeString,
+							Description: "The kind as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'kind' field",
+							Optional:    true,
+						},
+						"original_domain": {
+							Type:        schema.TypeString,
+							Description: "The domain as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'domain' field",
+							Optional:    true,
+						},
+						"original_email_address": {
+							Type:        schema.TypeString,
+							Description: "The email address as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'email_address' field",
+							Optional:    true,
+						},
+						"original_account": {
+							Type:        schema.TypeString,
+							Description: "The account as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'account' field",
+							Optional:    true,
+						},
+						"original_class_info": {
+							Type:        schema.TypeString,
+							Description: "The class info as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'class_info' field",
+							Optional:    true,
+						},
+						"original_login_server": {
+							Type:        schema.TypeString,
+							Description: "The login server as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'login_server' field",
+							Optional:    true,
+						},
+						"original_connection_string": {
+							Type:        schema.TypeString,
+							Description: "The connection string as configured in the PBIX, if applicable for the type of datasource. This will be the value replaced with the value in the 'connection_string' field",
+							Optional:    true,
+						},
+						"datasource_id": {
+							Type:        schema.TypeString,
+							Description: "The ID of the datasource to reconfigure. Can be used instead of the original values to select the datasource",
+							Optional:    true,
+						},
+						"credentials": {
+							Type:        schema.TypeList,
+							Description: "Credentials to set on the datasource once it has been reconfigured. Credentials for datasources on an on-premises gateway are encrypted with the public key of the gateway",
+							Optional:    true,
+							MaxItems:    1,
+							Elem: &schema.Resource{
+								Schema: credentialsSchema(),
+							},
+						},
+					},
+				},
+			},
+			"rewrite": {
+				Type:        schema.TypeList,
+				Description: "Rewrites of the Power Query queries in the PBIX, applied to a copy of the PBIX before it is uploaded. Useful for connectors whose datasources cannot be updated once uploaded. Rewrites are applied in order, and changing this value will require reuploading the PBIX",
+				Optional:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"server": {
+							Type:        schema.TypeString,
+							Description: "The server name to replace the 'original_server' with",
+							Optional:    true,
+						},
+						"original_server": {
+							Type:        schema.TypeString,
+							Description: "The server name as used in the queries. Text values that equal it are replaced",
+							Optional:    true,
+						},
+						"database": {
+							Type:        schema.TypeString,
+							Description: "The database name to replace the 'original_database' with",
+							Optional:    true,
+						},
+						"original_database": {
+							Type:        schema.TypeString,
+							Description: "The database name as used in the queries. Text values that equal it are replaced",
+							Optional:    true,
+						},
+						"url": {
+							Type:        schema.TypeString,
+							Description: "The URL to replace the 'original_url' with",
+							Optional:    true,
+						},
+						"original_url": {
+							Type:        schema.TypeString,
+							Description: "The URL as used in the queries. Text values that equal it are replaced",
+							Optional:    true,
+						},
+						"parameter_name": {
+							Type:        schema.TypeString,
+							Description: "The name of a parameter whose value is set to 'parameter_value' before the PBIX is uploaded",
+							Optional:    true,
+						},
+						"parameter_value": {
+							Type:        schema.TypeString,
+							Description: "The value to set the 'parameter_name' parameter to. Text parameters are given the value as text, other parameters are given the value as a Power Query expression",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"datasources": {
+				Type:        schema.TypeList,
+				Description: "The datasources of the deployed dataset, as reported by the service. Configured datasources are tracked against these by datasource ID.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"type": {
+							Type:        schema.TypeString,
+							Description: "The type of datasource",
+							Computed:    true,
+						},
+						"server": {
+							Type:        schema.TypeString,
+							Description: "The server name, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"database": {
+							Type:        schema.TypeString,
+							Description: "The database name, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"url": {
+							Type:        schema.TypeString,
+							Description: "The service URL, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"path": {
+							Type:        schema.TypeString,
+							Description: "The path, if applicable for the type of datasource. For example the file path of a File datasource or the path of an Extension datasource",
+							Computed:    true,
+						},
+						"kind": {
+							Type:        schema.TypeString,
+							Description: "The kind of an Extension datasource. For example SharePointList",
+							Computed:    true,
+						},
+						"domain": {
+							Type:        schema.TypeString,
+							Description: "The domain, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"email_address": {
+							Type:        schema.TypeString,
+							Description: "The email address, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"account": {
+							Type:        schema.TypeString,
+							Description: "The account, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"class_info": {
+							Type:        schema.TypeString,
+							Description: "The class info, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"login_server": {
+							Type:        schema.TypeString,
+							Description: "The login server, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"connection_string": {
+							Type:        schema.TypeString,
+							Description: "The connection string, if applicable for the type of datasource",
+							Computed:    true,
+						},
+						"gateway_id": {
+							Type:        schema.TypeString,
+							Description: "The ID of the gateway the datasource is bound to",
+							Computed:    true,
+						},
+						"datasource_id": {
+							Type:        schema.TypeString,
+							Description: "The ID of the datasource",
+							Computed:    true,
+						},
 					},
 				},
 			},
@@ -141,16 +427,115 @@ func ResourcePBIX() *schema.Resource {
 	}
 }
 
-func openContentReader(d *schema.ResourceData) (io.Reader, error) {
+func customizePBIXDiff(d *schema.ResourceDiff, meta interface{}) error {
+	// renaming in place uses the Fabric REST API, so in environments without it the PBIX is replaced instead
+	if client, ok := meta.(*powerbiapi.Client); ok && d.Id() != "" && d.HasChange("name") && !client.IsFabricAPIAvailable() {
+		err := d.ForceNew("name")
+		if err != nil {
+			return err
+		}
+	}
+
+	// reverting a republish made outside of terraform requires reuploading the PBIX, which the update does
+	// whenever a republish has been read
+	if d.Get("republished_import_id").(string) != "" {
+		return d.SetNewComputed("republished_import_id")
+	}
+	return nil
+}
+
+func openContentReader(d *schema.ResourceData) (io.ReadCloser, error) {
 	filepath := d.Get("source").(string)
-	return os.Open(filepath)
+
+	pipelineFuncs, err := pbixRewritePipelineFuncs(d)
+	if err != nil {
+		return nil, err
+	}
+	if len(pipelineFuncs) == 0 {
+		return os.Open(filepath)
+	}
+
+	rewrittenFile, err := ioutil.TempFile("", "*.pbix")
+	if err != nil {
+		return nil, err
+	}
+	rewrittenFile.Close()
+
+	err = pbixrewriter.RewritePbixFiles(filepath, rewrittenFile.Name(), pipelineFuncs)
+	if err != nil {
+		os.Remove(rewrittenFile.Name())
+		return nil, fmt.Errorf("Unable to rewrite PBIX %s: %s", filepath, err)
+	}
+
+	reader, err := os.Open(rewrittenFile.Name())
+	if err != nil {
+		os.Remove(rewrittenFile.Name())
+		return nil, err
+	}
+	return temporaryFile{reader}, nil
+}
+
+// temporaryFile is a file that is deleted once it is closed
+type temporaryFile struct {
+	*os.File
+}
+
+func (file temporaryFile) Close() error {
+	err := file.File.Close()
+	os.Remove(file.Name())
+	return err
+}
+
+// pbixRewritePipelineFuncs returns the pipeline funcs that rewrite the PBIX before it is uploaded
+func pbixRewritePipelineFuncs(d *schema.ResourceData) ([]pbixrewriter.PipelineFunc, error) {
+	rewrites := []struct {
+		originalKey  string
+		valueKey     string
+		pipelineFunc func(original string, value string) pbixrewriter.PipelineFunc
+	}{
+		{"original_server", "server", pbixrewriter.ReplaceServerPipelineFunc},
+		{"original_database", "database", pbixrewriter.ReplaceDatabasePipelineFunc},
+		{"original_url", "url", pbixrewriter.ReplaceURLPipelineFunc},
+		{"parameter_name", "parameter_value", pbixrewriter.SetParameterPipelineFunc},
+	}
+
+	var pipelineFuncs []pbixrewriter.PipelineFunc
+	for i, rewriteObj := range d.Get("rewrite").([]interface{}) {
+		rewriteObj, _ := rewriteObj.(map[string]interface{})
+
+		rewriteCount := 0
+		for _, rewrite := range rewrites {
+			original, _ := rewriteObj[rewrite.originalKey].(string)
+			value, _ := rewriteObj[rewrite.valueKey].(string)
+			if original == "" && value == "" {
+				continue
+			}
+			if original == "" {
+				return nil, fmt.Errorf("rewrite %d sets %s without %s", i, rewrite.valueKey, rewrite.originalKey)
+			}
+			pipelineFuncs = append(pipelineFuncs, rewrite.pipelineFunc(original, value))
+			rewriteCount++
+		}
+
+		if rewriteCount == 0 {
+			return nil, fmt.Errorf("rewrite %d does not set any values to rewrite", i)
+		}
+	}
+	return pipelineFuncs, nil
 }
 
 func createPBIX(d *schema.ResourceData, meta interface{}) error {
 
 	d.Partial(true)
 
-	err := createImport(d, meta)
+	name := d.Get("name").(string)
+	nameConflict := d.Get("name_conflict").(string)
+	err := checkPBIXNameConflict(d, meta, name, nameConflict)
+	if err != nil {
+		return err
+	}
+
+	err = createImport(d, meta, name, nameConflict)
 	if err != nil {
 		return err
 	}
@@ -159,6 +544,7 @@ func createPBIX(d *schema.ResourceData, meta interface{}) error {
 	if err != nil {
 		return err
 	}
+	d.Set("republished_import_id", "")
 
 	err = setPBIXParameters(d, meta)
 	if err != nil {
@@ -170,6 +556,21 @@ func createPBIX(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	err = takeOverPBIXDataset(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = bindPBIXDatasetToGateway(d, meta)
+	if err != nil {
+		return err
+	}
+
+	err = setPBIXDatasourceCredentials(d, meta)
+	if err != nil {
+		return err
+	}
+
 	if _, ok := d.GetOk("rebind_dataset_id"); ok {
 		err = rebindPBIXDataset(d, meta)
 		if err != nil {
@@ -177,6 +578,17 @@ func createPBIX(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	err = refreshPBIXDataset(d, meta, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return err
+	}
+
+	// datasources are read after all changes as updating and binding them can change their IDs
+	err = readPBIXDatasources(d, meta)
+	if err != nil {
+		return err
+	}
+
 	d.Partial(false)
 
 	return nil
@@ -194,6 +606,11 @@ func readPBIX(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	err = readPBIXRepublished(d, meta)
+	if err != nil {
+		return err
+	}
+
 	err = readPBIXParameters(d, meta)
 	if err != nil {
 		return err
@@ -204,11 +621,24 @@ func readPBIX(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
+	err = readPBIXOwnerAndGateway(d, meta)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
 func updatePBIX(d *schema.ResourceData, meta interface{}) error {
-	if d.HasChange("source") || d.HasChange("source_hash") || d.HasChange("datasource") {
+	// renaming first means any reupload overwrites the renamed report and dataset
+	if d.HasChange("name") {
+		err := renamePBIX(d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("source") || d.HasChange("source_hash") || d.HasChange("datasource") || d.HasChange("rewrite") || d.Get("republished_import_id").(string) != "" {
 
 		d.Partial(true)
 
@@ -218,7 +648,15 @@ func updatePBIX(d *schema.ResourceData, meta interface{}) error {
 			return err
 		}
 
-		err = createImport(d, meta)
+		// reuploads replace the report and dataset deployed by this resource, using the name they have in
+		// the workspace as it can differ from the configured name
+		deployedName := pbixDeployedName(d)
+		err = checkPBIXNameConflict(d, meta, deployedName, "CreateOrOverwrite")
+		if err != nil {
+			return err
+		}
+
+		err = createImport(d, meta, deployedName, "CreateOrOverwrite")
 		if err != nil {
 			return err
 		}
@@ -228,6 +666,10 @@ func updatePBIX(d *schema.ResourceData, meta interface{}) error {
 			return err
 		}
 
+		// the upload reverts any republish made outside of terraform
+		d.SetPartial("republished_import_id")
+		d.Set("republished_import_id", "")
+
 		err = setPBIXParameters(d, meta)
 		if err != nil {
 			return err
@@ -238,18 +680,17 @@ func updatePBIX(d *schema.ResourceData, meta interface{}) error {
 			return err
 		}
 
-		err = rebindPBIXDataset(d, meta)
+		err = takeOverPBIXDataset(d, meta)
 		if err != nil {
 			return err
 		}
 
-		d.Partial(false)
-
-		return nil
-	}
+		err = bindPBIXDatasetToGateway(d, meta)
+		if err != nil {
+			return err
+		}
 
-	if d.HasChange("rebind_dataset_id") {
-		err := unbindPBIXDataset(d, meta)
+		err = setPBIXDatasourceCredentials(d, meta)
 		if err != nil {
 			return err
 		}
@@ -258,18 +699,108 @@ func updatePBIX(d *schema.ResourceData, meta interface{}) error {
 		if err != nil {
 			return err
 		}
-	}
 
-	if d.HasChange("parameter") {
-		err := setPBIXParameters(d, meta)
+		err = refreshPBIXDataset(d, meta, d.Timeout(schema.TimeoutUpdate))
 		if err != nil {
 			return err
 		}
-	}
+
+		// datasources are read after all changes as updating and binding them can change their IDs
+		err = readPBIXDatasources(d, meta)
+		if err != nil {
+			return err
+		}
+
+		d.Partial(false)
+
+		return nil
+	}
+
+	if d.HasChange("rebind_dataset_id") {
+		err := unbindPBIXDataset(d, meta)
+		if err != nil {
+			return err
+		}
+
+		err = rebindPBIXDataset(d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("take_over") {
+		err := takeOverPBIXDataset(d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("take_over") || d.HasChange("gateway_id") || d.HasChange("gateway_datasource_ids") {
+		err := bindPBIXDatasetToGateway(d, meta)
+		if err != nil {
+			return err
+		}
+
+		// binding to a different gateway uses the credentials of the gateway datasources
+		err = setPBIXDatasourceCredentials(d, meta)
+		if err != nil {
+			return err
+		}
+
+		err = readPBIXDatasources(d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("parameter") {
+		err := setPBIXParameters(d, meta)
+		if err != nil {
+			return err
+		}
+
+		err = refreshPBIXDataset(d, meta, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
+func renamePBIX(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+	groupID := d.Get("workspace_id").(string)
+	name := d.Get("name").(string)
+
+	err := checkPBIXNameConflict(d, meta, name, "CreateOrOverwrite")
+	if err != nil {
+		return err
+	}
+
+	if reportID, reportIDOk := d.GetOk("report_id"); reportIDOk {
+		err := client.UpdateReportInGroup(groupID, reportID.(string), powerbiapi.UpdateReportInGroupRequest{
+			DisplayName: name,
+		})
+		if err != nil {
+			return fmt.Errorf("Unable to rename report %s to %s: %s", reportID, name, err)
+		}
+	}
+
+	if datasetID, datasetIDOk := d.GetOk("dataset_id"); datasetIDOk {
+		err := client.UpdateDatasetInGroup(groupID, datasetID.(string), powerbiapi.UpdateDatasetInGroupRequest{
+			DisplayName: name,
+		})
+		if err != nil {
+			return fmt.Errorf("Unable to rename dataset %s to %s: %s", datasetID, name, err)
+		}
+	}
+
+	d.SetPartial("deployed_name")
+	d.Set("deployed_name", name)
+	return nil
+}
+
 func deletePBIX(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*powerbiapi.Client)
 
@@ -292,19 +823,74 @@ func deletePBIX(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func createImport(d *schema.ResourceData, meta interface{}) error {
+// pbixDeployedName returns the name of the report and dataset in the workspace, which is only known once the
+// PBIX has been uploaded
+func pbixDeployedName(d *schema.ResourceData) string {
+	if deployedName := d.Get("deployed_name").(string); deployedName != "" {
+		return deployedName
+	}
+	return d.Get("name").(string)
+}
+
+// checkPBIXNameConflict ensures uploading the PBIX with the name will not overwrite a report or dataset with the
+// same name that is not tracked by this resource, unless adopting them is allowed
+func checkPBIXNameConflict(d *schema.ResourceData, meta interface{}, name string, nameConflict string) error {
+	if (nameConflict != "CreateOrOverwrite" && nameConflict != "Overwrite") || d.Get("adopt_existing").(bool) {
+		return nil
+	}
+
+	client := meta.(*powerbiapi.Client)
+	groupID := d.Get("workspace_id").(string)
+
+	trackedIDs := map[string]bool{}
+	for _, key := range []string{"report_id", "dataset_id", "report_original_dataset_id"} {
+		if id := d.Get(key).(string); id != "" {
+			trackedIDs[strings.ToLower(id)] = true
+		}
+	}
+
+	if !d.Get("skip_report").(bool) {
+		reports, err := client.GetReportsInGroup(groupID)
+		if err != nil {
+			return err
+		}
+		for _, report := range reports.Value {
+			if strings.EqualFold(report.Name, name) && !trackedIDs[strings.ToLower(report.ID)] {
+				return fmt.Errorf("Unable to upload PBIX %s. Report %s already exists in workspace %s and is not managed by this resource. Set adopt_existing to overwrite it", name, report.ID, groupID)
+			}
+		}
+	}
+
+	datasets, err := client.GetDatasetsInGroup(groupID)
+	if err != nil {
+		return err
+	}
+	for _, dataset := range datasets.Value {
+		// push datasets are never overwritten by an import
+		if strings.EqualFold(dataset.Name, name) && !dataset.AddRowsAPIEnabled && !trackedIDs[strings.ToLower(dataset.ID)] {
+			return fmt.Errorf("Unable to upload PBIX %s. Dataset %s already exists in workspace %s and is not managed by this resource. Set adopt_existing to overwrite it", name, dataset.ID, groupID)
+		}
+	}
+
+	return nil
+}
+
+func createImport(d *schema.ResourceData, meta interface{}, name string, nameConflict string) error {
 	client := meta.(*powerbiapi.Client)
 
 	reader, err := openContentReader(d)
 	if err != nil {
 		return err
 	}
+	defer reader.Close()
 
 	resp, err := client.PostImportInGroup(
 		d.Get("workspace_id").(string),
-		d.Get("name").(string),
-		"CreateOrOverwrite",
-		d.Get("skip_report").(bool),
+		powerbiapi.PostImportInGroupRequest{
+			DatasetDisplayName: name,
+			NameConflict:       nameConflict,
+			SkipReport:         d.Get("skip_report").(bool),
+		},
 		reader,
 	)
 	if err != nil {
@@ -329,9 +915,6 @@ func readImport(d *schema.ResourceData, meta interface{}, timeoutForSuccessfulIm
 		return err
 	}
 
-	d.SetPartial("name")
-	d.Set("name", im.Name)
-
 	// powerbi imports can be modified by some operations (such as rebind)
 	// in order to keep reference to the original report and original dataset
 	// we will only look them up once after creation
@@ -353,9 +936,106 @@ func readImport(d *schema.ResourceData, meta interface{}, timeoutForSuccessfulIm
 			d.Set("dataset_id", im.Datasets[0].ID)
 		}
 	}
+
+	d.SetPartial("import_updated_time")
+	d.Set("import_updated_time", im.UpdatedDateTime.Format(time.RFC3339Nano))
+
+	name, err := readPBIXName(d, meta, im)
+	if err != nil {
+		return err
+	}
+	d.SetPartial("deployed_name")
+	d.Set("deployed_name", name)
+
+	// generated unique names are derived from the configured name, which is kept to avoid renaming the PBIX
+	if d.Get("name_conflict").(string) != "GenerateUniqueName" {
+		d.SetPartial("name")
+		d.Set("name", name)
+	}
 	return nil
 }
 
+// readPBIXRepublished finds the latest import that republished the report or dataset since this resource last uploaded
+// the PBIX. Republishes overwrite the report and dataset, so are found by their IDs as well as the name
+func readPBIXRepublished(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+	groupID := d.Get("workspace_id").(string)
+
+	importUpdatedTime, err := time.Parse(time.RFC3339Nano, d.Get("import_updated_time").(string))
+	if err != nil {
+		return err
+	}
+
+	imports, err := client.GetImportsInGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	var republished *powerbiapi.GetImportsInGroupResponseItem
+	for i, im := range imports.Value {
+		if strings.EqualFold(im.ID, d.Id()) || !im.UpdatedDateTime.After(importUpdatedTime) || !isPBIXImport(d, im) {
+			continue
+		}
+		if republished == nil || im.UpdatedDateTime.After(republished.UpdatedDateTime) {
+			republished = &imports.Value[i]
+		}
+	}
+
+	if republished == nil {
+		d.Set("republished_import_id", "")
+	} else {
+		d.Set("republished_import_id", republished.ID)
+	}
+	return nil
+}
+
+// isPBIXImport returns whether the import has the name of the PBIX or imported its report or dataset
+func isPBIXImport(d *schema.ResourceData, im powerbiapi.GetImportsInGroupResponseItem) bool {
+	if strings.EqualFold(im.Name, pbixDeployedName(d)) {
+		return true
+	}
+	for _, report := range im.Reports {
+		if strings.EqualFold(report.ID, d.Get("report_id").(string)) {
+			return true
+		}
+	}
+	for _, dataset := range im.Datasets {
+		if strings.EqualFold(dataset.ID, d.Get("dataset_id").(string)) {
+			return true
+		}
+	}
+	return false
+}
+
+// readPBIXName reads the name from the report, or the dataset if there is no report, as the import keeps the
+// name they were imported with even after they are renamed
+func readPBIXName(d *schema.ResourceData, meta interface{}, im *powerbiapi.GetImportInGroupResponse) (string, error) {
+	client := meta.(*powerbiapi.Client)
+	groupID := d.Get("workspace_id").(string)
+
+	if reportID := d.Get("report_id").(string); reportID != "" {
+		report, err := client.GetReportInGroup(groupID, reportID)
+		if err == nil {
+			return report.Name, nil
+		}
+		if !isHTTP404Error(err) {
+			return "", err
+		}
+	}
+
+	if datasetID := d.Get("dataset_id").(string); datasetID != "" {
+		dataset, err := client.GetDatasetInGroup(groupID, datasetID)
+		if err == nil {
+			return dataset.Name, nil
+		}
+		if !isHTTP404Error(err) {
+			return "", err
+		}
+	}
+
+	return im.Name, nil
+}
+
 func setPBIXParameters(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*powerbiapi.Client)
@@ -438,23 +1118,39 @@ func setPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 				return fmt.Errorf("Unable to update datasources on a PBIX file that does not contain a dataset")
 			}
 
+			// datasources selected by ID are also selected by the gateway they are on
+			var apiDatasources *powerbiapi.GetDatasourcesInGroupResponse
 			updateDatasourcesRequest := powerbiapi.UpdateDatasourcesInGroupRequest{}
 			for _, datasourceObj := range datasourceList {
 				datasourceObj := datasourceObj.(map[string]interface{})
+				datasourceSelector := powerbiapi.UpdateDatasourcesInGroupRequestItemDatasourceSelector{
+					DatasourceType:    datasourceObj["type"].(string),
+					ConnectionDetails: expandPBIXDatasourceConnectionDetails(datasourceObj, "original_"),
+				}
+
+				if datasourceID := datasourceObj["datasource_id"].(string); datasourceID != "" {
+					if apiDatasources == nil {
+						var err error
+						apiDatasources, err = client.GetDatasourcesInGroup(groupID, datasetID.(string))
+						if err != nil {
+							return err
+						}
+					}
+
+					for _, apiDatasource := range apiDatasources.Value {
+						if strings.EqualFold(apiDatasource.DatasourceID, datasourceID) {
+							datasourceSelector.DatasourceID = apiDatasource.DatasourceID
+							datasourceSelector.GatewayID = apiDatasource.GatewayID
+						}
+					}
+					if datasourceSelector.DatasourceID == "" {
+						return fmt.Errorf("Unable to update datasource. Dataset %s has no datasource with ID %s", datasetID, datasourceID)
+					}
+				}
+
 				updateDatasourcesRequest.UpdateDetails = append(updateDatasourcesRequest.UpdateDetails, powerbiapi.UpdateDatasourcesInGroupRequestItem{
-					ConnectionDetails: powerbiapi.UpdateDatasourcesInGroupRequestItemConnectionDetails{
-						URL:      emptyStringToNil(datasourceObj["url"].(string)),
-						Database: emptyStringToNil(datasourceObj["database"].(string)),
-						Server:   emptyStringToNil(datasourceObj["server"].(string)),
-					},
-					DatasourceSelector: powerbiapi.UpdateDatasourcesInGroupRequestItemDatasourceSelector{
-						DatasourceType: datasourceObj["type"].(string),
-						ConnectionDetails: powerbiapi.UpdateDatasourcesInGroupRequestItemConnectionDetails{
-							URL:      emptyStringToNil(datasourceObj["original_url"].(string)),
-							Database: emptyStringToNil(datasourceObj["original_database"].(string)),
-							Server:   emptyStringToNil(datasourceObj["original_server"].(string)),
-						},
-					},
+					ConnectionDetails:  expandPBIXDatasourceConnectionDetails(datasourceObj, ""),
+					DatasourceSelector: datasourceSelector,
 				})
 			}
 
@@ -470,6 +1166,117 @@ func setPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
+// setPBIXDatasourceCredentials sets the credentials of the datasources matching each datasource with credentials
+func setPBIXDatasourceCredentials(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	datasetID, datasetOk := d.GetOk("dataset_id")
+	groupID := d.Get("workspace_id").(string)
+
+	var datasourcesWithCredentials []map[string]interface{}
+	for _, datasourceObj := range d.Get("datasource").(*schema.Set).List() {
+		datasourceObj := datasourceObj.(map[string]interface{})
+		if len(datasourceObj["credentials"].([]interface{})) > 0 {
+			datasourcesWithCredentials = append(datasourcesWithCredentials, datasourceObj)
+		}
+	}
+	if len(datasourcesWithCredentials) == 0 {
+		return nil
+	}
+	if !datasetOk {
+		return fmt.Errorf("Unable to set datasource credentials on a PBIX file that does not contain a dataset")
+	}
+
+	// the datasources are looked up again as updating and binding them can change their IDs
+	apiDatasources, err := client.GetDatasourcesInGroup(groupID, datasetID.(string))
+	if err != nil {
+		return err
+	}
+
+	for _, datasourceObj := range datasourcesWithCredentials {
+		credentialsObj := datasourceObj["credentials"].([]interface{})[0].(map[string]interface{})
+
+		matched := false
+		for _, apiDatasource := range apiDatasources.Value {
+			if !pbixDatasourceMatches(datasourceObj, apiDatasource) {
+				continue
+			}
+			matched = true
+
+			err := updateDatasourceCredentials(meta, apiDatasource.GatewayID, apiDatasource.DatasourceID, credentialsObj)
+			if err != nil {
+				return err
+			}
+		}
+		if !matched {
+			return fmt.Errorf("Unable to set credentials. No datasource of dataset %s has type '%s' and connection details url '%s', server '%s', database '%s'", datasetID, datasourceObj["type"], datasourceObj["url"], datasourceObj["server"], datasourceObj["database"])
+		}
+	}
+
+	return nil
+}
+
+// pbixDatasourceConnectionDetailKeys are the connection details a datasource can be reconfigured with
+var pbixDatasourceConnectionDetailKeys = []string{"server", "database", "url", "path", "kind", "domain", "email_address", "account", "class_info", "login_server", "connection_string"}
+
+// flattenPBIXDatasourceConnectionDetails returns the connection details of a datasource keyed by the names used in the schema
+func flattenPBIXDatasourceConnectionDetails(connectionDetails powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails) map[string]*string {
+	return map[string]*string{
+		"server":            connectionDetails.Server,
+		"database":          connectionDetails.Database,
+		"url":               connectionDetails.URL,
+		"path":              connectionDetails.Path,
+		"kind":              connectionDetails.Kind,
+		"domain":            connectionDetails.Domain,
+		"email_address":     connectionDetails.EmailAddress,
+		"account":           connectionDetails.Account,
+		"class_info":        connectionDetails.ClassInfo,
+		"login_server":      connectionDetails.LoginServer,
+		"connection_string": connectionDetails.ConnectionString,
+	}
+}
+
+// expandPBIXDatasourceConnectionDetails returns the connection details of a datasource block, read from the keys with the given prefix
+func expandPBIXDatasourceConnectionDetails(datasourceObj map[string]interface{}, prefix string) powerbiapi.UpdateDatasourcesInGroupRequestItemConnectionDetails {
+	get := func(key string) *string {
+		return emptyStringToNil(datasourceObj[prefix+key].(string))
+	}
+
+	return powerbiapi.UpdateDatasourcesInGroupRequestItemConnectionDetails{
+		Server:           get("server"),
+		Database:         get("database"),
+		URL:              get("url"),
+		Path:             get("path"),
+		Kind:             get("kind"),
+		Domain:           get("domain"),
+		EmailAddress:     get("email_address"),
+		Account:          get("account"),
+		ClassInfo:        get("class_info"),
+		LoginServer:      get("login_server"),
+		ConnectionString: get("connection_string"),
+	}
+}
+
+// pbixDatasourceMatches determines if a datasource of the dataset has the ID, type and connection details a datasource was reconfigured to
+func pbixDatasourceMatches(datasourceObj map[string]interface{}, apiDatasource powerbiapi.GetDatasourcesInGroupResponseItem) bool {
+	if datasourceObj["datasource_id"] != "" && !strings.EqualFold(datasourceObj["datasource_id"].(string), apiDatasource.DatasourceID) {
+		return false
+	}
+	if datasourceObj["type"] != "" && !strings.EqualFold(datasourceObj["type"].(string), apiDatasource.DatasourceType) {
+		return false
+	}
+
+	actualConnectionDetails := flattenPBIXDatasourceConnectionDetails(apiDatasource.ConnectionDetails)
+	for _, key := range pbixDatasourceConnectionDetailKeys {
+		expected := datasourceObj[key].(string)
+		actual := actualConnectionDetails[key]
+		if expected != "" && (actual == nil || !strings.EqualFold(expected, *actual)) {
+			return false
+		}
+	}
+	return true
+}
+
 func readPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 
 	client := meta.(*powerbiapi.Client)
@@ -477,6 +1284,7 @@ func readPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 	datasetID, datasetOk := d.GetOk("dataset_id")
 	groupID := d.Get("workspace_id").(string)
 	stateDatasources := d.Get("datasource").(*schema.Set)
+	previousDatasources := d.Get("datasources").([]interface{})
 
 	// some pbix do not have datasets, and therefore not all have datasources
 	if !datasetOk {
@@ -488,37 +1296,247 @@ func readPBIXDatasources(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	// Because datasource updates work in "find and replace" kind of semantic, it is
-	// impossible to track the values of individual datasources. However we can
-	// determine if there are no datasource that match our original replacement
+	// Because datasource updates work in "find and replace" kind of semantic, the datasources
+	// a replacement applied to are identified by the datasource IDs they had when last read.
+	// The actual values of those datasources are read back so drift shows the connection that changed
+	var datasources []interface{}
 	for _, stateDatasource := range stateDatasources.List() {
 		stateDatasourceObj := stateDatasource.(map[string]interface{})
-		anyAPIMatchesState := false
+
+		datasourceIDs := map[string]bool{}
+		for _, previousDatasource := range previousDatasources {
+			previousDatasourceObj := previousDatasource.(map[string]interface{})
+			if pbixDatasourceMatches(stateDatasourceObj, powerbiapi.GetDatasourcesInGroupResponseItem{
+				DatasourceID:      previousDatasourceObj["datasource_id"].(string),
+				DatasourceType:    previousDatasourceObj["type"].(string),
+				ConnectionDetails: powerbiapi.GetDatasourcesInGroupResponseItemConnectionDetails(expandPBIXDatasourceConnectionDetails(previousDatasourceObj, "")),
+			}) {
+				datasourceIDs[previousDatasourceObj["datasource_id"].(string)] = true
+			}
+		}
+
+		trackedAPIDatasources := []powerbiapi.GetDatasourcesInGroupResponseItem{}
 		for _, apiDatasource := range apiDatasources.Value {
-			apiMatchesState := (stateDatasourceObj["url"] == "" || stateDatasourceObj["url"] == *apiDatasource.ConnectionDetails.URL) &&
-				(stateDatasourceObj["server"] == "" || stateDatasourceObj["server"] == *apiDatasource.ConnectionDetails.Server) &&
-				(stateDatasourceObj["database"] == "" || stateDatasourceObj["database"] == *apiDatasource.ConnectionDetails.Database)
-			anyAPIMatchesState = anyAPIMatchesState || apiMatchesState
+			if datasourceIDs[apiDatasource.DatasourceID] {
+				trackedAPIDatasources = append(trackedAPIDatasources, apiDatasource)
+			}
 		}
 
-		if !anyAPIMatchesState {
-			if stateDatasourceObj["url"] != "" {
-				stateDatasourceObj["url"] = "???"
+		// without previously read datasources, such as after an import or when the PBIX was reuploaded
+		// and its datasources given new IDs, the datasources currently matching the replacement are tracked
+		if len(trackedAPIDatasources) == 0 {
+			for _, apiDatasource := range apiDatasources.Value {
+				if pbixDatasourceMatches(stateDatasourceObj, apiDatasource) {
+					trackedAPIDatasources = append(trackedAPIDatasources, apiDatasource)
+				}
 			}
-			if stateDatasourceObj["server"] != "" {
-				stateDatasourceObj["server"] = "???"
+		}
+
+		datasourceObj := map[string]interface{}{}
+		for key, value := range stateDatasourceObj {
+			datasourceObj[key] = value
+		}
+		for _, apiDatasource := range trackedAPIDatasources {
+			actualConnectionDetails := flattenPBIXDatasourceConnectionDetails(apiDatasource.ConnectionDetails)
+			for _, key := range pbixDatasourceConnectionDetailKeys {
+				readPBIXDatasourceConnectionDetail(datasourceObj, key, actualConnectionDetails[key])
 			}
-			if stateDatasourceObj["database"] != "" {
-				stateDatasourceObj["database"] = "???"
+		}
+
+		// no datasource has the replaced values
+		if len(trackedAPIDatasources) == 0 {
+			for _, key := range pbixDatasourceConnectionDetailKeys {
+				datasourceObj[key] = ""
 			}
 		}
+
+		datasources = append(datasources, datasourceObj)
 	}
 
 	d.SetPartial("datasource")
-	d.Set("datasource", stateDatasources)
+	d.Set("datasource", datasources)
+
+	d.SetPartial("datasources")
+	d.Set("datasources", flattenPBIXDatasources(apiDatasources.Value))
 	return nil
 }
 
+// readPBIXDatasourceConnectionDetail sets a configured connection detail to the actual value if it has drifted
+func readPBIXDatasourceConnectionDetail(datasourceObj map[string]interface{}, key string, actual *string) {
+	if datasourceObj[key] != "" && !strings.EqualFold(datasourceObj[key].(string), nilToEmptyString(actual)) {
+		datasourceObj[key] = nilToEmptyString(actual)
+	}
+}
+
+func flattenPBIXDatasources(apiDatasources []powerbiapi.GetDatasourcesInGroupResponseItem) []interface{} {
+	datasources := []interface{}{}
+	for _, apiDatasource := range apiDatasources {
+		datasourceObj := map[string]interface{}{
+			"type":          apiDatasource.DatasourceType,
+			"gateway_id":    apiDatasource.GatewayID,
+			"datasource_id": apiDatasource.DatasourceID,
+		}
+		for key, value := range flattenPBIXDatasourceConnectionDetails(apiDatasource.ConnectionDetails) {
+			datasourceObj[key] = nilToEmptyString(value)
+		}
+		datasources = append(datasources, datasourceObj)
+	}
+	return datasources
+}
+
+func takeOverPBIXDataset(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasetID, datasetOk := d.GetOk("dataset_id")
+
+	if !d.Get("take_over").(bool) {
+		return nil
+	}
+	if !datasetOk {
+		return fmt.Errorf("Unable to take over a PBIX file that does not contain a dataset")
+	}
+
+	err := client.TakeOverInGroup(groupID, datasetID.(string))
+	if err != nil {
+		return err
+	}
+
+	// remember who owns the dataset after take over so that others taking it over can be detected
+	dataset, err := client.GetDatasetInGroup(groupID, datasetID.(string))
+	if err != nil {
+		return err
+	}
+	d.SetPartial("take_over")
+	d.SetPartial("dataset_configured_by")
+	d.Set("dataset_configured_by", dataset.ConfiguredBy)
+	return nil
+}
+
+func bindPBIXDatasetToGateway(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasetID, datasetOk := d.GetOk("dataset_id")
+	gatewayID, gatewayOk := d.GetOk("gateway_id")
+
+	if !gatewayOk {
+		return nil
+	}
+	if !datasetOk {
+		return fmt.Errorf("Unable to bind a PBIX file that does not contain a dataset to a gateway")
+	}
+
+	// binding to a gateway the dataset cannot use gives an unhelpful error, so check it first
+	gateways, err := client.DiscoverGatewaysInGroup(groupID, datasetID.(string))
+	if err != nil {
+		return err
+	}
+	gatewayAvailable := false
+	var availableGatewayIDs []string
+	for _, gateway := range gateways.Value {
+		gatewayAvailable = gatewayAvailable || strings.EqualFold(gateway.ID, gatewayID.(string))
+		availableGatewayIDs = append(availableGatewayIDs, gateway.ID)
+	}
+	if !gatewayAvailable {
+		return fmt.Errorf("Dataset %s cannot be bound to gateway %s. Gateways available to the dataset are %v", datasetID, gatewayID, availableGatewayIDs)
+	}
+
+	err = client.BindToGatewayInGroup(groupID, datasetID.(string), powerbiapi.BindToGatewayInGroupRequest{
+		GatewayObjectID:     gatewayID.(string),
+		DatasourceObjectIDs: convertToStringSlice(d.Get("gateway_datasource_ids").(*schema.Set).List()),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetPartial("gateway_id")
+	d.SetPartial("gateway_datasource_ids")
+	return nil
+}
+
+func readPBIXOwnerAndGateway(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasetID, datasetOk := d.GetOk("dataset_id")
+
+	// some pbix do not have datasets, and therefore have no owner or gateway
+	if !datasetOk {
+		return nil
+	}
+
+	dataset, err := client.GetDatasetInGroup(groupID, datasetID.(string))
+	if err != nil {
+		return err
+	}
+
+	// if someone else has since taken over the dataset we no longer own it
+	if configuredBy := d.Get("dataset_configured_by").(string); d.Get("take_over").(bool) && configuredBy != "" && !strings.EqualFold(configuredBy, dataset.ConfiguredBy) {
+		d.Set("take_over", false)
+	}
+	d.Set("dataset_configured_by", dataset.ConfiguredBy)
+
+	// cloud datasources also report a gateway, so the binding is only tracked when a gateway is configured
+	if _, gatewayOk := d.GetOk("gateway_id"); !gatewayOk {
+		return nil
+	}
+
+	apiDatasources, err := client.GetDatasourcesInGroup(groupID, datasetID.(string))
+	if err != nil {
+		return err
+	}
+
+	// the dataset is considered bound if any of its datasources use the gateway, as
+	// datasources that do not need a gateway can remain on their cloud gateway
+	gatewayID := d.Get("gateway_id").(string)
+	boundGatewayID := ""
+	gatewayDatasourceIDs := make([]string, 0, len(apiDatasources.Value))
+	for _, apiDatasource := range apiDatasources.Value {
+		if boundGatewayID == "" || strings.EqualFold(apiDatasource.GatewayID, gatewayID) {
+			boundGatewayID = apiDatasource.GatewayID
+		}
+		if strings.EqualFold(apiDatasource.GatewayID, gatewayID) {
+			gatewayDatasourceIDs = append(gatewayDatasourceIDs, apiDatasource.DatasourceID)
+		}
+	}
+	if len(apiDatasources.Value) > 0 && !strings.EqualFold(boundGatewayID, gatewayID) {
+		d.Set("gateway_id", boundGatewayID)
+	}
+
+	// gateway datasources matched by connection details are not tracked, and cloud
+	// datasources that remain on their own gateway are never part of the binding
+	if d.Get("gateway_datasource_ids").(*schema.Set).Len() > 0 {
+		d.Set("gateway_datasource_ids", gatewayDatasourceIDs)
+	}
+
+	return nil
+}
+
+func refreshPBIXDataset(d *schema.ResourceData, meta interface{}, timeoutForRefresh time.Duration) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+	datasetID, datasetOk := d.GetOk("dataset_id")
+
+	if !d.Get("refresh_dataset").(bool) {
+		return nil
+	}
+	if !datasetOk {
+		return fmt.Errorf("Unable to refresh a PBIX file that does not contain a dataset")
+	}
+
+	resp, err := client.RefreshDatasetInGroup(groupID, datasetID.(string), powerbiapi.RefreshDatasetInGroupRequest{
+		NotifyOption: "NoNotification",
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = client.WaitForRefreshInGroupToComplete(groupID, datasetID.(string), resp, timeoutForRefresh)
+	return err
+}
+
 func rebindPBIXDataset(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*powerbiapi.Client)
 
@@ -1,9 +1,12 @@
 package powerbi
 
 import (
+	"archive/zip"
+	"bytes"
 	"encoding/hex"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/rand"
 	"os"
 	"path/filepath"
@@ -14,8 +17,10 @@ import (
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/pbixrewriter"
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
 )
 
@@ -822,7 +827,982 @@ func TestAccPBIX_datasources(t *testing.T) {
 	})
 }
 
+func TestUnitPBIX_basic(t *testing.T) {
+	var updatedTime time.Time
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			// first step creates the resource
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_pbix" "test" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					name = "Unit Test PBIX"
+					source = "./resource_pbix_test_sample1.pbix"
+					source_hash = "${filemd5("./resource_pbix_test_sample1.pbix")}"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					setUpdatedTime("powerbi_pbix.test", &updatedTime),
+					testCheckDatasetExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+					testCheckReportExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+					resource.TestCheckResourceAttrSet("powerbi_pbix.test", "dataset_id"),
+					resource.TestCheckResourceAttrSet("powerbi_pbix.test", "report_id"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "name", "Unit Test PBIX"),
+				),
+			},
+			// changing the source re-uploads the pbix
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_pbix" "test" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					name = "Unit Test PBIX"
+					source = "./resource_pbix_test_sample2.pbix"
+					source_hash = "${filemd5("./resource_pbix_test_sample2.pbix")}"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckUpdatedAfter("powerbi_pbix.test", &updatedTime),
+					testCheckDatasetExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+					testCheckReportExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+				),
+			},
+			// deletes the resource
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckDatasetDoesNotExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+					testCheckReportDoesNotExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+					testCheckResourceRemoved("powerbi_pbix.test"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_parameters_and_datasources(t *testing.T) {
+	var datasetID string
+	var groupID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.DefaultParameters = []powerbiapitest.Parameter{
+		{Name: "ParamOne", Type: "Text", CurrentValue: "ParamOneValue"},
+		{Name: "ParamTwo", Type: "Text", CurrentValue: "ParamTwoValue"},
+	}
+	server.DefaultDatasources = []powerbiapitest.Datasource{
+		{DatasourceType: "OData", ConnectionDetails: map[string]string{"url": "https://services.odata.org/V3/OData/OData.svc"}},
+	}
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		source_hash = "${filemd5("./resource_pbix_test_sample1.pbix")}"
+		parameter {
+			name = "ParamOne"
+			value = "NewParamValueOne"
+		}
+		datasource {
+			type = "OData"
+			url = "https://services.odata.org/V3/(S(kbiqo1qkby04vnobw0li0fcp))/OData/OData.svc"
+			original_url = "https://services.odata.org/V3/OData/OData.svc"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_pbix.test", "dataset_id", &datasetID),
+					set("powerbi_pbix.test", "workspace_id", &groupID),
+					testCheckParameter("powerbi_pbix.test", "ParamOne", "NewParamValueOne"),
+					testCheckParameter("powerbi_pbix.test", "ParamTwo", "ParamTwoValue"),
+					testCheckURLDatasource("powerbi_pbix.test", "https://services.odata.org/V3/(S(kbiqo1qkby04vnobw0li0fcp))/OData/OData.svc"),
+				),
+			},
+			// parameter drift is corrected without reuploading
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.UpdateParametersInGroup(groupID, datasetID, powerbiapi.UpdateParametersInGroupRequest{
+						UpdateDetails: []powerbiapi.UpdateParametersInGroupRequestItem{
+							{Name: "ParamOne", NewValue: "DriftedValue"},
+						},
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckParameter("powerbi_pbix.test", "ParamOne", "NewParamValueOne"),
+				),
+			},
+			// datasource drift is corrected by reuploading
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.UpdateDatasourcesInGroup(groupID, datasetID, powerbiapi.UpdateDatasourcesInGroupRequest{
+						UpdateDetails: []powerbiapi.UpdateDatasourcesInGroupRequestItem{
+							{
+								ConnectionDetails: powerbiapi.UpdateDatasourcesInGroupRequestItemConnectionDetails{
+									URL: emptyStringToNil("https://google.com"),
+								},
+								DatasourceSelector: powerbiapi.UpdateDatasourcesInGroupRequestItemDatasourceSelector{
+									DatasourceType: "OData",
+								},
+							},
+						},
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckURLDatasource("powerbi_pbix.test", "https://services.odata.org/V3/(S(kbiqo1qkby04vnobw0li0fcp))/OData/OData.svc"),
+					testCheckParameter("powerbi_pbix.test", "ParamOne", "NewParamValueOne"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_rebind_dataset(t *testing.T) {
+	var dataset1ID string
+	var dataset2ID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_pbix" "dataset" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					name = "Unit Test PBIX Dataset"
+					source = "./resource_pbix_test_sample1.pbix"
+					skip_report = true
+				}
+
+				resource "powerbi_pbix" "report" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					name = "Unit Test PBIX Report"
+					source = "./resource_pbix_test_sample1.pbix"
+					rebind_dataset_id = "${powerbi_pbix.dataset.dataset_id}"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_pbix.dataset", "dataset_id", &dataset1ID),
+					set("powerbi_pbix.report", "dataset_id", &dataset2ID),
+					testCheckResourceAttrNotSet("powerbi_pbix.dataset", "report_id"),
+					testCheckReportDataset("powerbi_pbix.report", &dataset1ID),
+				),
+			},
+			// removing the rebind reverts to the original dataset
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_pbix" "dataset" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					name = "Unit Test PBIX Dataset"
+					source = "./resource_pbix_test_sample1.pbix"
+					skip_report = true
+				}
+
+				resource "powerbi_pbix" "report" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					name = "Unit Test PBIX Report"
+					source = "./resource_pbix_test_sample1.pbix"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckReportDataset("powerbi_pbix.report", &dataset2ID),
+				),
+			},
+		},
+	})
+}
+
 // TempFileName generates a temporary filename for use in testing or whatever
+func TestUnitPBIX_refresh_dataset(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.DefaultParameters = []powerbiapitest.Parameter{
+		{Name: "ParamOne", Type: "Text", CurrentValue: "ParamOneValue"},
+	}
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		refresh_dataset = true
+		parameter {
+			name = "ParamOne"
+			value = "%s"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "FirstValue"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckDatasetRefreshes("powerbi_pbix.test", "dataset_id", 1),
+				),
+			},
+			// changing parameters refreshes the dataset again
+			{
+				Config: fmt.Sprintf(configTemplate, "SecondValue"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckParameter("powerbi_pbix.test", "ParamOne", "SecondValue"),
+					testCheckDatasetRefreshes("powerbi_pbix.test", "dataset_id", 2),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_take_over_and_gateway(t *testing.T) {
+	var datasetID string
+	var groupID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.DefaultDatasources = []powerbiapitest.Datasource{
+		{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql.contoso.local", "database": "Sales"}},
+	}
+	gatewayDatasource := powerbiapitest.GatewayDatasource{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql.contoso.local", "database": "Sales"}}
+	gateway1 := server.AddGateway(powerbiapitest.Gateway{Name: "Gateway 1", Datasources: []powerbiapitest.GatewayDatasource{gatewayDatasource}})
+	gateway2 := server.AddGateway(powerbiapitest.Gateway{Name: "Gateway 2", Datasources: []powerbiapitest.GatewayDatasource{gatewayDatasource, gatewayDatasource}})
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		take_over = true
+		%s
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, `gateway_id = "`+gateway1.ID+`"`),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_pbix.test", "dataset_id", &datasetID),
+					set("powerbi_pbix.test", "workspace_id", &groupID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "dataset_configured_by", "00000000-0000-0000-0000-00000000000b"),
+					testCheckDatasourceGateway("powerbi_pbix.test", gateway1.ID, gateway1.Datasources[0].ID),
+				),
+			},
+			// another owner rebinding the dataset is detected and reverted
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.BindToGatewayInGroup(groupID, datasetID, powerbiapi.BindToGatewayInGroupRequest{GatewayObjectID: gateway2.ID})
+					server.SetDatasetOwner(datasetID, "someone@contoso.com")
+				},
+				Config: fmt.Sprintf(configTemplate, `gateway_id = "`+gateway1.ID+`"`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "take_over", "true"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "dataset_configured_by", "00000000-0000-0000-0000-00000000000b"),
+					testCheckDatasourceGateway("powerbi_pbix.test", gateway1.ID, gateway1.Datasources[0].ID),
+				),
+			},
+			// specific gateway datasources can be selected
+			{
+				Config: fmt.Sprintf(configTemplate, `
+				gateway_id = "`+gateway2.ID+`"
+				gateway_datasource_ids = ["`+gateway2.Datasources[1].ID+`"]
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckDatasourceGateway("powerbi_pbix.test", gateway2.ID, gateway2.Datasources[1].ID),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(configTemplate, `gateway_id = "00000000-0000-0000-0000-000000000001"`),
+				ExpectError: regexp.MustCompile("cannot be bound to gateway 00000000-0000-0000-0000-000000000001"),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_gateway_with_cloud_datasources(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.DefaultDatasources = []powerbiapitest.Datasource{
+		{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql.contoso.local", "database": "Sales"}},
+		{DatasourceType: "Web", ConnectionDetails: map[string]string{"url": "https://contoso.com/data"}, Cloud: true},
+	}
+	gateway := server.AddGateway(powerbiapitest.Gateway{Name: "Gateway", Datasources: []powerbiapitest.GatewayDatasource{
+		{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql.contoso.local", "database": "Sales"}},
+	}})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			// only the gateway datasource is recorded, so the cloud datasource does not cause drift
+			{
+				Config: fmt.Sprintf(`
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+
+				resource "powerbi_pbix" "test" {
+					workspace_id = "${powerbi_workspace.test.id}"
+					name = "Unit Test PBIX"
+					source = "./resource_pbix_test_sample1.pbix"
+					gateway_id = "%s"
+					gateway_datasource_ids = ["%s"]
+				}
+				`, gateway.ID, gateway.Datasources[0].ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "gateway_id", gateway.ID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "gateway_datasource_ids.#", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_datasource_credentials(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.DefaultDatasources = []powerbiapitest.Datasource{
+		{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql-dev.contoso.local", "database": "Sales"}},
+		{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql-dev.contoso.local", "database": "Inventory"}},
+	}
+	gateway := server.AddGateway(powerbiapitest.Gateway{
+		Name: "Unit Test Gateway",
+		Datasources: []powerbiapitest.GatewayDatasource{
+			{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql.contoso.local", "database": "Sales"}},
+			{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql.contoso.local", "database": "Inventory"}},
+		},
+	})
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		take_over = true
+		gateway_id = "%s"
+		datasource {
+			type = "Sql"
+			original_server = "sql-dev.contoso.local"
+			original_database = "Sales"
+			server = "sql.contoso.local"
+			database = "Sales"
+			credentials {
+				credential_type = "Windows"
+				username = "CONTOSO\\svc-powerbi"
+				password = "%s"
+			}
+		}
+		datasource {
+			type = "Sql"
+			original_server = "sql-dev.contoso.local"
+			original_database = "Inventory"
+			server = "sql.contoso.local"
+			database = "Inventory"
+			credentials {
+				credential_type = "Basic"
+				username = "inventory-reader"
+				password = "inventory-password"
+			}
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, "first-password"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckPBIXDatasourceCredentials(server, "powerbi_pbix.test", "Sales", `{"credentialData":[{"name":"username","value":"CONTOSO\\svc-powerbi"},{"name":"password","value":"first-password"}]}`),
+					testCheckPBIXDatasourceCredentials(server, "powerbi_pbix.test", "Inventory", `{"credentialData":[{"name":"username","value":"inventory-reader"},{"name":"password","value":"inventory-password"}]}`),
+				),
+			},
+			// changed credentials are set again
+			{
+				Config: fmt.Sprintf(configTemplate, gateway.ID, "second-password"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckPBIXDatasourceCredentials(server, "powerbi_pbix.test", "Sales", `{"credentialData":[{"name":"username","value":"CONTOSO\\svc-powerbi"},{"name":"password","value":"second-password"}]}`),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_datasources_drift(t *testing.T) {
+	var datasetID string
+	var groupID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	server.DefaultDatasources = []powerbiapitest.Datasource{
+		{DatasourceType: "OData", ConnectionDetails: map[string]string{"url": "https://services.odata.org/V3/OData/OData.svc"}},
+		{DatasourceType: "Sql", ConnectionDetails: map[string]string{"server": "sql-dev.contoso.local", "database": "Sales"}},
+	}
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		datasource {
+			type = "Sql"
+			original_server = "sql-dev.contoso.local"
+			server = "sql.contoso.local"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			// datasources without some connection details are read
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_pbix.test", "dataset_id", &datasetID),
+					set("powerbi_pbix.test", "workspace_id", &groupID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.#", "2"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.0.type", "OData"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.0.url", "https://services.odata.org/V3/OData/OData.svc"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.0.server", ""),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.1.type", "Sql"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.1.server", "sql.contoso.local"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.1.database", "Sales"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.1.url", ""),
+					resource.TestCheckResourceAttrSet("powerbi_pbix.test", "datasources.1.datasource_id"),
+					resource.TestCheckResourceAttrSet("powerbi_pbix.test", "datasources.1.gateway_id"),
+				),
+			},
+			// no changes are planned while the datasources match
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			// the drifted datasource is detected by its ID
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.UpdateDatasourcesInGroup(groupID, datasetID, powerbiapi.UpdateDatasourcesInGroupRequest{
+						UpdateDetails: []powerbiapi.UpdateDatasourcesInGroupRequestItem{
+							{
+								ConnectionDetails: powerbiapi.UpdateDatasourcesInGroupRequestItemConnectionDetails{
+									Server: emptyStringToNil("sql-drifted.contoso.local"),
+								},
+								DatasourceSelector: powerbiapi.UpdateDatasourcesInGroupRequestItemDatasourceSelector{
+									DatasourceType: "Sql",
+								},
+							},
+						},
+					})
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// datasource drift is corrected by reuploading
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.1.server", "sql.contoso.local"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_datasource_connection_details(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	fileDatasourceID := "2d8cc5d0-8f39-4ff6-8a35-3f8c7d1f2b6e"
+	server.DefaultDatasources = []powerbiapitest.Datasource{
+		{DatasourceType: "Extension", ConnectionDetails: map[string]string{"kind": "SharePointList", "path": "https://contoso.sharepoint.com/sites/dev"}},
+		{DatasourceID: fileDatasourceID, DatasourceType: "File", ConnectionDetails: map[string]string{"path": "C:\\data\\dev.xlsx"}},
+	}
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		datasource {
+			type = "Extension"
+			original_kind = "SharePointList"
+			original_path = "https://contoso.sharepoint.com/sites/dev"
+			kind = "SharePointList"
+			path = "https://contoso.sharepoint.com/sites/prod"
+		}
+		datasource {
+			datasource_id = "` + fileDatasourceID + `"
+			path = "C:\\data\\prod.xlsx"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.#", "2"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.0.kind", "SharePointList"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.0.path", "https://contoso.sharepoint.com/sites/prod"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.1.datasource_id", fileDatasourceID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "datasources.1.path", "C:\\data\\prod.xlsx"),
+				),
+			},
+			// no changes are planned while the datasources match
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_rewrite(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		rewrite {
+			original_url = "https://services.odata.org/V3/OData/OData.svc/"
+			url = "https://services.odata.org/V4/OData/OData.svc/"
+		}
+		rewrite {
+			parameter_name = "ParamOne"
+			parameter_value = "%s"
+		}
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "RewrittenValue"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckPBIXFormulasContain(server, "powerbi_pbix.test", `OData.Feed("https://services.odata.org/V4/OData/OData.svc/"`),
+					testCheckPBIXFormulasContain(server, "powerbi_pbix.test", `shared ParamOne = "RewrittenValue" meta`),
+				),
+			},
+			// changing a rewrite reuploads the PBIX
+			{
+				Config: fmt.Sprintf(configTemplate, "ChangedValue"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckPBIXFormulasContain(server, "powerbi_pbix.test", `shared ParamOne = "ChangedValue" meta`),
+				),
+			},
+			// rewrites that do not match the queries fail
+			{
+				Config: strings.NewReplacer(
+					`original_url = "https://services.odata.org/V3/OData/OData.svc/"`, `original_server = "sql-dev.contoso.local"`,
+					`url = "https://services.odata.org/V4/OData/OData.svc/"`, `server = "sql.contoso.local"`,
+				).Replace(fmt.Sprintf(configTemplate, "ChangedValue")),
+				ExpectError: regexp.MustCompile("Server 'sql-dev.contoso.local' was not found in the PBIX queries"),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_rename(t *testing.T) {
+	var reportID string
+	var datasetID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "%s"
+		source = "./%s"
+		source_hash = "${filemd5("./%s")}"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "Unit Test PBIX", "resource_pbix_test_sample1.pbix", "resource_pbix_test_sample1.pbix"),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_pbix.test", "report_id", &reportID),
+					set("powerbi_pbix.test", "dataset_id", &datasetID),
+				),
+			},
+			// renaming keeps the same report and dataset
+			{
+				Config: fmt.Sprintf(configTemplate, "Renamed PBIX", "resource_pbix_test_sample1.pbix", "resource_pbix_test_sample1.pbix"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_pbix.test", "report_id", &reportID),
+					resource.TestCheckResourceAttrPtr("powerbi_pbix.test", "dataset_id", &datasetID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "name", "Renamed PBIX"),
+					testCheckReportExistsInWorkspace("powerbi_workspace.test", "Renamed PBIX"),
+					testCheckDatasetExistsInWorkspace("powerbi_workspace.test", "Renamed PBIX"),
+					testCheckReportDoesNotExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+					testCheckDatasetDoesNotExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+				),
+			},
+			// renaming while reuploading overwrites the renamed report and dataset
+			{
+				Config: fmt.Sprintf(configTemplate, "Unit Test PBIX", "resource_pbix_test_sample2.pbix", "resource_pbix_test_sample2.pbix"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_pbix.test", "report_id", &reportID),
+					resource.TestCheckResourceAttrPtr("powerbi_pbix.test", "dataset_id", &datasetID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "name", "Unit Test PBIX"),
+					testCheckReportDoesNotExistsInWorkspace("powerbi_workspace.test", "Renamed PBIX"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_rename_without_fabric(t *testing.T) {
+	var reportID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+
+	// sovereign clouds do not have the Fabric REST API
+	testAccProvider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		environment := server.Environment()
+		environment.FabricAPIEndpoint = ""
+		return server.ClientWithEnvironment(environment)
+	}
+
+	configTemplate := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "%s"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(configTemplate, "Unit Test PBIX"),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_pbix.test", "report_id", &reportID),
+				),
+			},
+			// renaming replaces the report and dataset as they cannot be renamed in place
+			{
+				Config: fmt.Sprintf(configTemplate, "Renamed PBIX"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceAttrNotEquals("powerbi_pbix.test", "report_id", &reportID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "name", "Renamed PBIX"),
+					testCheckReportExistsInWorkspace("powerbi_workspace.test", "Renamed PBIX"),
+					testCheckReportDoesNotExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_name_conflict(t *testing.T) {
+	var groupID string
+	var existingReportID string
+	var reportID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+
+	workspaceConfig := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+	`
+	pbixConfigTemplate := `
+	resource "powerbi_pbix" "%s" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		%s
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: workspaceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_workspace.test", "id", &groupID),
+				),
+			},
+			// a report published outside of terraform is not overwritten
+			{
+				PreConfig: func() {
+					client, err := server.Client()
+					if err != nil {
+						t.Fatal(err)
+					}
+					resp, err := client.PostImportInGroup(groupID, powerbiapi.PostImportInGroupRequest{
+						DatasetDisplayName: "Unit Test PBIX",
+						NameConflict:       "CreateOrOverwrite",
+					}, bytes.NewReader([]byte("manually published")))
+					if err != nil {
+						t.Fatal(err)
+					}
+					im, err := client.GetImportInGroup(groupID, resp.ID)
+					if err != nil {
+						t.Fatal(err)
+					}
+					existingReportID = im.Reports[0].ID
+				},
+				Config:      workspaceConfig + fmt.Sprintf(pbixConfigTemplate, "test", ""),
+				ExpectError: regexp.MustCompile("Report [^ ]+ already exists in workspace [^ ]+ and is not managed by this resource"),
+			},
+			{
+				Config:      workspaceConfig + fmt.Sprintf(pbixConfigTemplate, "test", `name_conflict = "Abort"`),
+				ExpectError: regexp.MustCompile("Content named 'Unit Test PBIX' already exists"),
+			},
+			{
+				Config:      workspaceConfig + fmt.Sprintf(pbixConfigTemplate, "test", `name_conflict = "Overwrite"`),
+				ExpectError: regexp.MustCompile("Report [^ ]+ already exists in workspace [^ ]+ and is not managed by this resource"),
+			},
+			// a unique name is generated while keeping the configured name
+			{
+				Config: workspaceConfig + fmt.Sprintf(pbixConfigTemplate, "test", `name_conflict = "GenerateUniqueName"`),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckReportExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX (2)"),
+					set("powerbi_pbix.test", "report_id", &reportID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "name", "Unit Test PBIX"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "deployed_name", "Unit Test PBIX (2)"),
+				),
+			},
+			// reuploads overwrite the report with the generated name rather than the existing report
+			{
+				Config: workspaceConfig + fmt.Sprintf(pbixConfigTemplate, "test", `
+				name_conflict = "GenerateUniqueName"
+				source_hash = "2"
+				`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_pbix.test", "report_id", &reportID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "deployed_name", "Unit Test PBIX (2)"),
+					testCheckReportExistsInWorkspace("powerbi_workspace.test", "Unit Test PBIX"),
+				),
+			},
+			// the existing report is overwritten once adopting it is allowed
+			{
+				Config: workspaceConfig + fmt.Sprintf(pbixConfigTemplate, "adopted", `adopt_existing = true`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_pbix.adopted", "report_id", &existingReportID),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitPBIX_republished(t *testing.T) {
+	var groupID string
+	var importID string
+	var reportID string
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+
+	pbixContent, err := ioutil.ReadFile("./resource_pbix_test_sample1.pbix")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_workspace.test", "id", &groupID),
+					set("powerbi_pbix.test", "id", &importID),
+					set("powerbi_pbix.test", "report_id", &reportID),
+					resource.TestCheckResourceAttrSet("powerbi_pbix.test", "import_updated_time"),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "republished_import_id", ""),
+				),
+			},
+			// republishing outside of terraform is detected
+			{
+				PreConfig: func() {
+					client, err := server.Client()
+					if err != nil {
+						t.Fatal(err)
+					}
+					_, err = client.PostImportInGroup(groupID, powerbiapi.PostImportInGroupRequest{
+						DatasetDisplayName: "Unit Test PBIX",
+						NameConflict:       "CreateOrOverwrite",
+					}, bytes.NewReader([]byte("republished from desktop")))
+					if err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config:             config,
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+			// and reverted by reuploading the PBIX, which is a new import
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckResourceAttrNotEquals("powerbi_pbix.test", "id", &importID),
+					resource.TestCheckResourceAttrPtr("powerbi_pbix.test", "report_id", &reportID),
+					resource.TestCheckResourceAttr("powerbi_pbix.test", "republished_import_id", ""),
+					testCheckImportContent(server, "powerbi_pbix.test", string(pbixContent)),
+				),
+			},
+		},
+	})
+}
+
+func testCheckPBIXFormulasContain(server *powerbiapitest.Server, pbixResourceName string, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[pbixResourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", pbixResourceName)
+		}
+
+		content := server.ImportContent(rs.Primary.ID)
+		pbix, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		if err != nil {
+			return err
+		}
+		formulas, err := pbixrewriter.ReadDataMashupFormulas(pbix)
+		if err != nil {
+			return err
+		}
+
+		for _, formula := range formulas {
+			if strings.Contains(formula, expected) {
+				return nil
+			}
+		}
+		return fmt.Errorf("Expecting the uploaded PBIX queries to contain %s. Found %v", expected, formulas)
+	}
+}
+
+func testCheckPBIXDatasourceCredentials(server *powerbiapitest.Server, pbixResourceName string, database string, expectedCredentials string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[pbixResourceName]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", pbixResourceName)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		datasources, err := client.GetDatasourcesInGroup(rs.Primary.Attributes["workspace_id"], rs.Primary.Attributes["dataset_id"])
+		if err != nil {
+			return err
+		}
+
+		for _, datasource := range datasources.Value {
+			if datasource.ConnectionDetails.Database == nil || *datasource.ConnectionDetails.Database != database {
+				continue
+			}
+			actualCredentials := server.DatasourceCredentials(datasource.GatewayID, datasource.DatasourceID)
+			if actualCredentials != expectedCredentials {
+				return fmt.Errorf("Expecting %s datasource credentials %s. Found %s", database, expectedCredentials, actualCredentials)
+			}
+			return nil
+		}
+		return fmt.Errorf("No datasource found for database %s", database)
+	}
+}
+
 func TempFileName(prefix, suffix string) string {
 	randBytes := make([]byte, 16)
 	rand.Read(randBytes)
@@ -1175,3 +2155,29 @@ func testCheckURLDatasource(pbixResourceName string, expectedValue string) resou
 		return fmt.Errorf("Expecting datasource with field url value %s to exist. Only the urls %v were found in the datasources", expectedValue, urlValues)
 	}
 }
+
+func testCheckDatasourceGateway(pbixResourceName string, expectedGatewayID string, expectedDatasourceID string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		datasetID, err := getResourceProperty(s, pbixResourceName, "dataset_id")
+		if err != nil {
+			return err
+		}
+		groupID, err := getResourceProperty(s, pbixResourceName, "workspace_id")
+		if err != nil {
+			return err
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		datasources, err := client.GetDatasourcesInGroup(groupID, datasetID)
+		if err != nil {
+			return err
+		}
+
+		for _, datasource := range datasources.Value {
+			if datasource.GatewayID != expectedGatewayID || datasource.DatasourceID != expectedDatasourceID {
+				return fmt.Errorf("Expecting datasource to be bound to gateway %s datasource %s. Found gateway %s datasource %s", expectedGatewayID, expectedDatasourceID, datasource.GatewayID, datasource.DatasourceID)
+			}
+		}
+		return nil
+	}
+}
@@ -278,6 +278,78 @@ func TestAccRefreshSchedule_skew(t *testing.T) {
 	})
 }
 
+func TestUnitRefreshSchedule_basic(t *testing.T) {
+	var datasetID string
+	var groupID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_pbix" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+
+	resource "powerbi_refresh_schedule" "test" {
+		dataset_id = "${powerbi_pbix.test.dataset_id}"
+		workspace_id = "${powerbi_pbix.test.workspace_id}"
+		enabled = %t
+		days = ["Monday", "Wednesday", "Friday"]
+		times = ["09:00", "17:30"]
+		local_time_zone_id = "Pacific Standard Time"
+		notify_option = "NoNotification"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, true),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_refresh_schedule.test", "dataset_id", &datasetID),
+					set("powerbi_refresh_schedule.test", "workspace_id", &groupID),
+					testCheckRefreshSchedule("powerbi_refresh_schedule.test", powerbiapi.GetRefreshScheduleInGroupResponse{
+						Enabled:         true,
+						Days:            []string{"Monday", "Wednesday", "Friday"},
+						Times:           []string{"09:00", "17:30"},
+						LocalTimeZoneID: "Pacific Standard Time",
+						NotifyOption:    "NoNotification",
+					}),
+				),
+			},
+			// skew is corrected, and the schedule can be disabled
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.UpdateRefreshScheduleInGroup(groupID, datasetID, powerbiapi.UpdateRefreshScheduleInGroupRequest{
+						Value: powerbiapi.UpdateRefreshScheduleInGroupRequestValue{
+							LocalTimeZoneID: convertStringToPointer("UTC"),
+						},
+					})
+				},
+				Config: fmt.Sprintf(config, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_refresh_schedule.test", "enabled", "false"),
+					testCheckRefreshSchedule("powerbi_refresh_schedule.test", powerbiapi.GetRefreshScheduleInGroupResponse{
+						Enabled:         false,
+						Days:            []string{"Monday", "Wednesday", "Friday"},
+						Times:           []string{"09:00", "17:30"},
+						LocalTimeZoneID: "Pacific Standard Time",
+						NotifyOption:    "NoNotification",
+					}),
+				),
+			},
+		},
+	})
+}
+
 func testCheckRefreshSchedule(scheduleRefreshResourceName string, expectedRefreshSchedule powerbiapi.GetRefreshScheduleInGroupResponse) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		datasetID, err := getResourceProperty(s, scheduleRefreshResourceName, "dataset_id")
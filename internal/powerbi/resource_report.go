@@ -0,0 +1,120 @@
+package powerbi
+
+import (
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// ResourceReport represents a Power BI report cloned from an existing report
+func ResourceReport() *schema.Resource {
+	return &schema.Resource{
+		Create: createReport,
+		Read:   readReport,
+		Update: updateReport,
+		Delete: deleteReport,
+
+		Schema: map[string]*schema.Schema{
+			"workspace_id": {
+				Type:        schema.TypeString,
+				Description: "Workspace ID in which the report will be created.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Description: "Name of the report.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"source_workspace_id": {
+				Type:        schema.TypeString,
+				Description: "Workspace ID containing the report to clone.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"source_report_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the report to clone.",
+				Required:    true,
+				ForceNew:    true,
+			},
+			"dataset_id": {
+				Type:        schema.TypeString,
+				Description: "ID of the dataset the report is bound to. Changing this will rebind the report. If not set the report is bound to the same dataset as the source report.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"web_url": {
+				Type:        schema.TypeString,
+				Description: "The web URL of the report.",
+				Computed:    true,
+			},
+			"embed_url": {
+				Type:        schema.TypeString,
+				Description: "The embed URL of the report.",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func createReport(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	resp, err := client.CloneReportInGroup(d.Get("source_workspace_id").(string), d.Get("source_report_id").(string), powerbiapi.CloneReportInGroupRequest{
+		Name:              d.Get("name").(string),
+		TargetModelID:     d.Get("dataset_id").(string),
+		TargetWorkspaceID: d.Get("workspace_id").(string),
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(resp.ID)
+
+	return readReport(d, meta)
+}
+
+func readReport(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	groupID := d.Get("workspace_id").(string)
+
+	report, err := client.GetReportInGroup(groupID, d.Id())
+	if isHTTP404Error(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.SetId(report.ID)
+	d.Set("name", report.Name)
+	d.Set("dataset_id", report.DatasetID)
+	d.Set("web_url", report.WebURL)
+	d.Set("embed_url", report.EmbedURL)
+
+	return nil
+}
+
+func updateReport(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	if d.HasChange("dataset_id") {
+		err := client.RebindReportInGroup(d.Get("workspace_id").(string), d.Id(), powerbiapi.RebindReportInGroupRequest{
+			DatasetID: d.Get("dataset_id").(string),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return readReport(d, meta)
+}
+
+func deleteReport(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	return client.DeleteReportInGroup(d.Get("workspace_id").(string), d.Id())
+}
@@ -0,0 +1,96 @@
+package powerbi
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+func TestUnitReport_basic(t *testing.T) {
+	var reportID string
+	var sourceDatasetID string
+	var otherDatasetID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	configTemplate := `
+	resource "powerbi_workspace" "source" {
+		name = "Unit Test Source Workspace"
+	}
+
+	resource "powerbi_workspace" "target" {
+		name = "Unit Test Target Workspace"
+	}
+
+	resource "powerbi_pbix" "source" {
+		workspace_id = powerbi_workspace.source.id
+		name = "Unit Test PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+	}
+
+	resource "powerbi_pbix" "other" {
+		workspace_id = powerbi_workspace.target.id
+		name = "Unit Test Other PBIX"
+		source = "./resource_pbix_test_sample1.pbix"
+		skip_report = true
+	}
+
+	resource "powerbi_report" "test" {
+		workspace_id = powerbi_workspace.target.id
+		name = "Unit Test Cloned Report"
+		source_workspace_id = powerbi_workspace.source.id
+		source_report_id = powerbi_pbix.source.report_id
+		%s
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			// without a dataset the clone stays bound to the source dataset
+			{
+				Config: fmt.Sprintf(configTemplate, ""),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_report.test", "id", &reportID),
+					set("powerbi_pbix.source", "dataset_id", &sourceDatasetID),
+					set("powerbi_pbix.other", "dataset_id", &otherDatasetID),
+					resource.TestCheckResourceAttrPair("powerbi_report.test", "dataset_id", "powerbi_pbix.source", "dataset_id"),
+					resource.TestCheckResourceAttrSet("powerbi_report.test", "web_url"),
+					resource.TestCheckResourceAttrSet("powerbi_report.test", "embed_url"),
+					testCheckReportBoundTo("powerbi_report.test", &sourceDatasetID),
+				),
+			},
+			// changing the dataset rebinds the same report
+			{
+				Config: fmt.Sprintf(configTemplate, "dataset_id = powerbi_pbix.other.dataset_id"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPtr("powerbi_report.test", "id", &reportID),
+					testCheckReportBoundTo("powerbi_report.test", &otherDatasetID),
+				),
+			},
+		},
+	})
+}
+
+func testCheckReportBoundTo(rn string, expectedDatasetID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", rn)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		report, err := client.GetReportInGroup(rs.Primary.Attributes["workspace_id"], rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if report.DatasetID != *expectedDatasetID {
+			return fmt.Errorf("Expecting report %v to be bound to dataset %v. Found dataset %v", rs.Primary.ID, *expectedDatasetID, report.DatasetID)
+		}
+		return nil
+	}
+}
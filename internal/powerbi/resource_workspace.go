@@ -5,6 +5,7 @@ import (
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
 )
 
 // ResourceWorkspace represents a Power BI workspace
@@ -22,14 +23,31 @@ func ResourceWorkspace() *schema.Resource {
 			"name": {
 				Type:        schema.TypeString,
 				Required:    true,
-				ForceNew:    true,
-				Description: "Name of the workspace.",
+				Description: "Name of the workspace. Renaming an existing workspace requires Power BI admin permissions.",
 			},
 			"capacity_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "Capacity ID to be assigned to workspace.",
 			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Description of the workspace. Requires Power BI admin permissions.",
+			},
+			"default_dataset_storage_format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"Small", "Large"}, false),
+				Description:  "Default storage format for datasets in the workspace. Any value from `Small` or `Large`. Large requires the workspace to be on a capacity. Requires Power BI admin permissions.",
+			},
+			"contacts": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Email addresses of the users or groups to contact about the workspace. Requires Power BI admin permissions.",
+			},
 		},
 	}
 }
@@ -55,6 +73,13 @@ func createWorkspace(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if hasAdminWorkspaceSettings(d) {
+		err := updateWorkspaceAsAdmin(d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
 	return readWorkspace(d, meta)
 }
 
@@ -76,6 +101,20 @@ func readWorkspace(d *schema.ResourceData, meta interface{}) error {
 		} else {
 			d.Set("capacity_id", "")
 		}
+
+		// settings are only available through the admin API, so only read them when
+		// they are being managed to avoid requiring admin permissions for every workspace
+		if hasAdminWorkspaceSettings(d) {
+			adminWorkspace, err := client.GetGroupAsAdmin(d.Id())
+			if err != nil {
+				return err
+			}
+			if adminWorkspace != nil {
+				d.Set("description", adminWorkspace.Description)
+				d.Set("default_dataset_storage_format", adminWorkspace.DefaultDatasetStorageFormat)
+				d.Set("contacts", adminWorkspace.Contacts)
+			}
+		}
 	}
 
 	return nil
@@ -83,6 +122,13 @@ func readWorkspace(d *schema.ResourceData, meta interface{}) error {
 
 func updateWorkspace(d *schema.ResourceData, meta interface{}) error {
 
+	if d.HasChanges("name", "description", "default_dataset_storage_format", "contacts") {
+		err := updateWorkspaceAsAdmin(d, meta)
+		if err != nil {
+			return err
+		}
+	}
+
 	if d.HasChange("capacity_id") {
 		if capacityID := d.Get("capacity_id").(string); capacityID == "" {
 			d.Set("capacity_id", "00000000-0000-0000-0000-000000000000")
@@ -103,6 +149,32 @@ func deleteWorkspace(d *schema.ResourceData, meta interface{}) error {
 	return client.DeleteGroup(d.Id())
 }
 
+func hasAdminWorkspaceSettings(d *schema.ResourceData) bool {
+	_, hasDescription := d.GetOk("description")
+	_, hasDefaultDatasetStorageFormat := d.GetOk("default_dataset_storage_format")
+	_, hasContacts := d.GetOk("contacts")
+	return hasDescription || hasDefaultDatasetStorageFormat || hasContacts
+}
+
+func updateWorkspaceAsAdmin(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*powerbiapi.Client)
+
+	request := powerbiapi.UpdateGroupAsAdminRequest{
+		Name: d.Get("name").(string),
+	}
+	if d.IsNewResource() || d.HasChange("description") {
+		request.Description = convertStringToPointer(d.Get("description").(string))
+	}
+	if d.IsNewResource() || d.HasChange("default_dataset_storage_format") {
+		request.DefaultDatasetStorageFormat = emptyStringToNil(d.Get("default_dataset_storage_format").(string))
+	}
+	if d.IsNewResource() || d.HasChange("contacts") {
+		request.Contacts = convertStringSliceToPointer(convertToStringSlice(d.Get("contacts").(*schema.Set).List()))
+	}
+
+	return client.UpdateGroupAsAdmin(d.Id(), request)
+}
+
 func assignToCapacity(d *schema.ResourceData, meta interface{}) error {
 	client := meta.(*powerbiapi.Client)
 
@@ -179,6 +179,66 @@ func TestAccWorkspaceAccess_skew(t *testing.T) {
 	})
 }
 
+func TestUnitWorkspaceAccess_basic(t *testing.T) {
+	var groupID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+
+	resource "powerbi_workspace_access" "test" {
+		workspace_id = "${powerbi_workspace.test.id}"
+		group_user_access_right = "%s"
+		email_address = "user@example.com"
+		principal_type = "User"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(config, "Admin"),
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_workspace.test", "id", &groupID),
+					testCheckGroupUserExistsInWorkspace("powerbi_workspace.test", "user@example.com"),
+					resource.TestCheckResourceAttr("powerbi_workspace_access.test", "id", "Unit Test Workspace/user@example.com"),
+				),
+			},
+			// access right skew is corrected
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.UpdateGroupUser(groupID, powerbiapi.UpdateGroupUserRequest{
+						Identifier:           "user@example.com",
+						PrincipalType:        "User",
+						GroupUserAccessRight: "Viewer",
+					})
+				},
+				Config: fmt.Sprintf(config, "Admin"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_workspace_access.test", "group_user_access_right", "Admin"),
+				),
+			},
+			{
+				Config: fmt.Sprintf(config, "Member"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_workspace_access.test", "group_user_access_right", "Member"),
+				),
+			},
+			{
+				ResourceName:      "powerbi_workspace_access.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
 func testCheckGroupUserExistsInWorkspace(workspaceResourceName string, expectedIdentifier string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		groupID, err := getResourceID(s, workspaceResourceName)
@@ -3,10 +3,12 @@ package powerbi
 import (
 	"fmt"
 	"os"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/terraform"
@@ -178,6 +180,242 @@ func TestAccWorkspace_skew(t *testing.T) {
 	})
 }
 
+func TestUnitWorkspace_basic(t *testing.T) {
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckWorkspaceExistsWithName("powerbi_workspace.test", "Unit Test Workspace"),
+					resource.TestCheckResourceAttrSet("powerbi_workspace.test", "id"),
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "capacity_id", ""),
+				),
+			},
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace - Updated"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckWorkspaceExistsWithName("powerbi_workspace.test", "Unit Test Workspace - Updated"),
+				),
+			},
+			{
+				ResourceName:      "powerbi_workspace.test",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestUnitWorkspace_capacity(t *testing.T) {
+	server, teardown := testUnitSetup(t)
+	defer teardown()
+	capacity := server.AddCapacity(powerbiapitest.Capacity{DisplayName: "Unit Test Capacity", SKU: "A1"})
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+					capacity_id = "%s"
+				}
+				`, capacity.ID),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "capacity_id", capacity.ID),
+				),
+			},
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "capacity_id", ""),
+				),
+			},
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+					capacity_id = "not-a-capacity"
+				}
+				`,
+				ExpectError: regexp.MustCompile("Capacity id not-a-capacity not found"),
+			},
+		},
+	})
+}
+
+func TestUnitWorkspace_skew(t *testing.T) {
+	var workspaceID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name = "Unit Test Workspace"
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_workspace.test", "id", &workspaceID),
+				),
+			},
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.DeleteGroup(workspaceID)
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckWorkspaceExistsWithName("powerbi_workspace.test", "Unit Test Workspace"),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitWorkspace_rename(t *testing.T) {
+	var workspaceID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_workspace.test", "id", &workspaceID),
+				),
+			},
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name = "Unit Test Workspace - Renamed"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckWorkspaceExistsWithName("powerbi_workspace.test", "Unit Test Workspace - Renamed"),
+					resource.TestCheckResourceAttrPtr("powerbi_workspace.test", "id", &workspaceID),
+				),
+			},
+		},
+	})
+}
+
+func TestUnitWorkspace_settings(t *testing.T) {
+	var workspaceID string
+	_, teardown := testUnitSetup(t)
+	defer teardown()
+
+	config := `
+	resource "powerbi_workspace" "test" {
+		name                           = "Unit Test Workspace"
+		description                    = "Managed by terraform"
+		default_dataset_storage_format = "Large"
+		contacts                       = ["owner@example.com"]
+	}
+	`
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckPowerbiWorkspaceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					set("powerbi_workspace.test", "id", &workspaceID),
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "description", "Managed by terraform"),
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "default_dataset_storage_format", "Large"),
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "contacts.#", "1"),
+				),
+			},
+			// changes made outside of terraform are reverted
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*powerbiapi.Client)
+					client.UpdateGroupAsAdmin(workspaceID, powerbiapi.UpdateGroupAsAdminRequest{
+						Name:        "Unit Test Workspace",
+						Description: convertStringToPointer("Changed outside terraform"),
+						Contacts:    convertStringSliceToPointer([]string{}),
+					})
+				},
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckWorkspaceAdminSettings("powerbi_workspace.test", "Managed by terraform", 1),
+				),
+			},
+			{
+				Config: `
+				resource "powerbi_workspace" "test" {
+					name                           = "Unit Test Workspace"
+					default_dataset_storage_format = "Small"
+				}
+				`,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "description", ""),
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "default_dataset_storage_format", "Small"),
+					resource.TestCheckResourceAttr("powerbi_workspace.test", "contacts.#", "0"),
+					testCheckWorkspaceAdminSettings("powerbi_workspace.test", "", 0),
+				),
+			},
+		},
+	})
+}
+
+func testCheckWorkspaceAdminSettings(rn string, expectedDescription string, expectedContactCount int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[rn]
+		if !ok {
+			return fmt.Errorf("not found: %s", rn)
+		}
+
+		client := testAccProvider.Meta().(*powerbiapi.Client)
+		workspace, err := client.GetGroupAsAdmin(rs.Primary.ID)
+		if err != nil {
+			return err
+		}
+		if workspace == nil {
+			return fmt.Errorf("Workspace %s not found", rs.Primary.ID)
+		}
+		if workspace.Description != expectedDescription {
+			return fmt.Errorf("Expected description '%s', found '%s'", expectedDescription, workspace.Description)
+		}
+		if len(workspace.Contacts) != expectedContactCount {
+			return fmt.Errorf("Expected %d contacts, found %d", expectedContactCount, len(workspace.Contacts))
+		}
+		return nil
+	}
+}
+
 func testCheckWorkspaceExistsWithName(rn string, expectedName string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[rn]
@@ -41,6 +41,13 @@ func emptyStringToNil(input string) *string {
 	return &input
 }
 
+func nilToEmptyString(input *string) string {
+	if input == nil {
+		return ""
+	}
+	return *input
+}
+
 func isHTTP404Error(err error) bool {
 	if httpErr, isHTTPErr := toHTTPUnsuccessfulError(err); isHTTPErr && httpErr.Response.StatusCode == 404 {
 		return true
@@ -3,16 +3,71 @@ package powerbiapi
 import (
 	"fmt"
 	"net/url"
+	"strconv"
 )
 
 // UpdateGroupAsAdminRequest represents the request to the UpdateGroupAsAdmin API
 type UpdateGroupAsAdminRequest struct {
-	Name string `json:"name"`
+	Name                        string    `json:"name"`
+	Description                 *string   `json:"description,omitempty"`
+	DefaultDatasetStorageFormat *string   `json:"defaultDatasetStorageFormat,omitempty"`
+	Contacts                    *[]string `json:"contacts,omitempty"`
+}
+
+// GetGroupsAsAdminResponse represents the response from the GetGroupsAsAdmin API
+type GetGroupsAsAdminResponse struct {
+	Value []GetGroupsAsAdminResponseItem
+}
+
+// GetGroupsAsAdminResponseItem represents an item returned within GetGroupsAsAdminResponse
+type GetGroupsAsAdminResponseItem struct {
+	ID                          string
+	Name                        string
+	Type                        string
+	State                       string
+	IsOnDedicatedCapacity       bool
+	CapacityID                  string
+	Description                 string
+	DefaultDatasetStorageFormat string
+	Contacts                    []string
 }
 
 // UpdateGroupAsAdmin updates a workspace
 func (client *Client) UpdateGroupAsAdmin(groupID string, request UpdateGroupAsAdminRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/admin/groups/%s", url.PathEscape(groupID))
+	url := client.buildURL("/admin/groups/%s", url.PathEscape(groupID))
 	return client.doJSON("PATCH", url, request, nil)
 }
+
+// GetGroupsAsAdmin returns a list of workspaces for the organization. Top is required by the API
+func (client *Client) GetGroupsAsAdmin(filter string, top int, skip int) (*GetGroupsAsAdminResponse, error) {
+
+	queryParams := url.Values{}
+	queryParams.Add("$top", strconv.Itoa(top))
+	if filter != "" {
+		queryParams.Add("$filter", filter)
+	}
+	if skip > 0 {
+		queryParams.Add("$skip", strconv.Itoa(skip))
+	}
+
+	var respObj GetGroupsAsAdminResponse
+	err := client.doJSON("GET", client.buildURL("/admin/groups?%s", queryParams.Encode()), nil, &respObj)
+
+	return &respObj, err
+}
+
+// GetGroupAsAdmin returns a single workspace including the details only available to admins
+func (client *Client) GetGroupAsAdmin(groupID string) (*GetGroupsAsAdminResponseItem, error) {
+
+	groups, err := client.GetGroupsAsAdmin(fmt.Sprintf("id eq '%s'", groupID), 1, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(groups.Value) == 0 {
+		return nil, nil
+	}
+
+	return &groups.Value[0], nil
+}
@@ -1,9 +1,6 @@
 package powerbiapi
 
-import (
-	"fmt"
-	"net/url"
-)
+import "net/url"
 
 // GroupAssignToCapacityRequest represents the request for Assigning capacity to group API.
 type GroupAssignToCapacityRequest struct {
@@ -31,7 +28,7 @@ type CapacityAdmins string
 
 // GroupAssignToCapacity assigns capcity to a workspace
 func (client *Client) GroupAssignToCapacity(groupID string, request GroupAssignToCapacityRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/AssignToCapacity", url.PathEscape(groupID))
+	url := client.buildURL("/groups/%s/AssignToCapacity", url.PathEscape(groupID))
 	err := client.doJSON("POST", url, &request, nil)
 
 	return err
@@ -40,7 +37,7 @@ func (client *Client) GroupAssignToCapacity(groupID string, request GroupAssignT
 // GetCapacities Returns a list of capacities the user has access to.
 func (client *Client) GetCapacities() (*GetCapacitiesResponse, error) {
 	var respObj GetCapacitiesResponse
-	err := client.doJSON("GET", "https://api.powerbi.com/v1.0/myorg/capacities", nil, &respObj)
+	err := client.doJSON("GET", client.buildURL("/capacities"), nil, &respObj)
 
 	return &respObj, err
 }
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"crypto/tls"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"net/textproto"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
@@ -17,24 +20,90 @@ import (
 // Client allows calling the Power BI service
 type Client struct {
 	*http.Client
+	baseURL       string
+	fabricBaseURL string
+
+	// LargeImportThreshold is the size in bytes above which files are imported through a temporary upload
+	// location rather than posted directly, as the service rejects posted files larger than 1 GB
+	LargeImportThreshold int64
 }
 
+// DefaultLargeImportThreshold is the default size above which files are imported through a temporary upload location
+const DefaultLargeImportThreshold = 1 << 30
+
 //NewClientWithPasswordAuth creates a Power BI REST API client using password authentication with delegated permissions
-func NewClientWithPasswordAuth(tenant string, clientID string, clientSecret string, username string, password string) (*Client, error) {
-	return newClient(func(httpClient *http.Client) (string, error) {
-		return getAuthTokenWithPassword(httpClient, tenant, clientID, clientSecret, username, password)
+func NewClientWithPasswordAuth(environment Environment, tenant string, clientID string, clientSecret string, username string, password string) (*Client, error) {
+	return newClient(environment, func(httpClient *http.Client) (*tokenResponse, error) {
+		return getAuthTokenWithPassword(httpClient, environment, tenant, clientID, clientSecret, username, password)
 	})
 }
 
 //NewClientWithClientCredentialAuth creates a Power BI REST API client using client credentials with application permissions
-func NewClientWithClientCredentialAuth(tenant string, clientID string, clientSecret string) (*Client, error) {
+func NewClientWithClientCredentialAuth(environment Environment, tenant string, clientID string, clientSecret string) (*Client, error) {
 
-	return newClient(func(httpClient *http.Client) (string, error) {
-		return getAuthTokenWithClientCredentials(httpClient, tenant, clientID, clientSecret)
+	return newClient(environment, func(httpClient *http.Client) (*tokenResponse, error) {
+		return getAuthTokenWithClientCredentials(httpClient, environment, tenant, clientID, clientSecret)
 	})
 }
 
-func newClient(getAuthToken func(httpClient *http.Client) (string, error)) (*Client, error) {
+//NewClientWithClientCertificateAuth creates a Power BI REST API client using a client certificate with application permissions.
+//The certificate data can be PEM or PFX, with the password only used for PFX
+func NewClientWithClientCertificateAuth(environment Environment, tenant string, clientID string, certificateData []byte, certificatePassword string) (*Client, error) {
+	certificate, err := parseClientCertificate(certificateData, certificatePassword)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(environment, func(httpClient *http.Client) (*tokenResponse, error) {
+		return getAuthTokenWithClientAssertion(httpClient, environment, tenant, clientID, func() (string, error) {
+			return certificate.createClientAssertion(environment.tokenURL(tenant), clientID)
+		})
+	})
+}
+
+//NewClientWithAzureCLIAuth creates a Power BI REST API client using the account logged into the Azure CLI.
+//If tenant is empty the tenant of the current Azure CLI subscription is used
+func NewClientWithAzureCLIAuth(environment Environment, tenant string) (*Client, error) {
+	return newClient(environment, func(httpClient *http.Client) (*tokenResponse, error) {
+		return getAuthTokenWithAzureCLI(environment, tenant)
+	})
+}
+
+//NewClientWithAccessToken creates a Power BI REST API client using an access token that has already been acquired.
+//The token is not refreshed so the client can only be used until the token expires
+func NewClientWithAccessToken(environment Environment, accessToken string) (*Client, error) {
+	return newClient(environment, func(httpClient *http.Client) (*tokenResponse, error) {
+		return &tokenResponse{AccessToken: accessToken}, nil
+	})
+}
+
+//NewClientWithMSIAuth creates a Power BI REST API client using an Azure managed identity with application permissions.
+//The client ID is only required when the host has multiple user assigned identities
+func NewClientWithMSIAuth(environment Environment, msiEndpoint string, clientID string) (*Client, error) {
+	if msiEndpoint == "" {
+		msiEndpoint = DefaultMSIEndpoint
+	}
+
+	return newClient(environment, func(httpClient *http.Client) (*tokenResponse, error) {
+		return getAuthTokenWithMSI(httpClient, environment, msiEndpoint, clientID)
+	})
+}
+
+//NewClientWithOIDCAuth creates a Power BI REST API client using workload identity federation with application permissions.
+//The federated token is taken from oidcToken if provided, otherwise it is read from oidcTokenFilePath
+func NewClientWithOIDCAuth(environment Environment, tenant string, clientID string, oidcToken string, oidcTokenFilePath string) (*Client, error) {
+	if oidcToken == "" && oidcTokenFilePath == "" {
+		return nil, fmt.Errorf("Either an OIDC token or an OIDC token file path is required")
+	}
+
+	return newClient(environment, func(httpClient *http.Client) (*tokenResponse, error) {
+		return getAuthTokenWithClientAssertion(httpClient, environment, tenant, clientID, func() (string, error) {
+			return readOIDCToken(oidcToken, oidcTokenFilePath)
+		})
+	})
+}
+
+func newClient(environment Environment, getAuthToken func(httpClient *http.Client) (*tokenResponse, error)) (*Client, error) {
 
 	// PowerBI has lots of intermittant TLS handshake issues, these settings
 	// seem to reduce the amount of issues encountered
@@ -62,11 +131,38 @@ func newClient(getAuthToken func(httpClient *http.Client) (string, error)) (*Cli
 		),
 	}
 
+	fabricBaseURL := ""
+	if environment.FabricAPIEndpoint != "" {
+		fabricBaseURL = strings.TrimRight(environment.FabricAPIEndpoint, "/") + "/v1"
+	}
+
 	return &Client{
-		httpClient,
+		Client:               httpClient,
+		baseURL:              strings.TrimRight(environment.APIEndpoint, "/") + "/v1.0/myorg",
+		fabricBaseURL:        fabricBaseURL,
+		LargeImportThreshold: DefaultLargeImportThreshold,
 	}, nil
 }
 
+// buildURL creates an absolute URL to the Power BI REST API from a path relative to /v1.0/myorg
+func (client *Client) buildURL(pathFormat string, a ...interface{}) string {
+	return client.baseURL + fmt.Sprintf(pathFormat, a...)
+}
+
+// buildFabricURL creates an absolute URL to the Fabric REST API from a path relative to /v1. Power BI access
+// tokens are accepted by the Fabric REST API, so the same client can call both
+func (client *Client) buildFabricURL(pathFormat string, a ...interface{}) (string, error) {
+	if client.fabricBaseURL == "" {
+		return "", fmt.Errorf("The Fabric REST API is not available in this environment")
+	}
+	return client.fabricBaseURL + fmt.Sprintf(pathFormat, a...), nil
+}
+
+// IsFabricAPIAvailable returns whether the Fabric REST API can be called in the environment of the client
+func (client *Client) IsFabricAPIAvailable() bool {
+	return client.fabricBaseURL != ""
+}
+
 func (client *Client) doJSON(method string, url string, body interface{}, response interface{}) error {
 
 	httpRequest, err := newJSONRequest(method, url, body)
@@ -84,10 +180,12 @@ func (client *Client) doJSON(method string, url string, body interface{}, respon
 
 func (client *Client) doMultipartJSON(method string, url string, body io.Reader, response interface{}) error {
 
-	httpRequest, err := newMultipartRequest(method, url, body)
+	httpRequest, streamedBody, err := newMultipartRequest(method, url, body)
 	if err != nil {
 		return err
 	}
+	// round trippers that fail before sending the request may leave the body streaming
+	defer streamedBody.Close()
 
 	httpResponse, err := client.Do(httpRequest)
 	if err != nil {
@@ -118,30 +216,106 @@ func newJSONRequest(method string, url string, body interface{}) (*http.Request,
 	return httpRequest, nil
 }
 
-func newMultipartRequest(method string, url string, reader io.Reader) (*http.Request, error) {
+func newMultipartRequest(method string, url string, reader io.Reader) (*http.Request, io.Closer, error) {
 
-	// Create multipart writer
-	var buffer bytes.Buffer
-	writer := multipart.NewWriter(&buffer)
+	// The multipart body is streamed from the reader as the request is sent, so large
+	// files are not held in memory. Only bodies streamed from seekable readers, such as
+	// files, can be streamed again to replay the request
+	body := &multipartBody{
+		reader:   reader,
+		boundary: multipart.NewWriter(nil).Boundary(),
+	}
+	if seeker, ok := reader.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			body.seeker = seeker
+			body.start = start
+		}
+	}
 
-	partWriter, err := writer.CreatePart(textproto.MIMEHeader{})
+	bodyReader, err := body.open()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Copy everything from reader in writer
-	if _, err = io.Copy(partWriter, reader); err != nil {
-		return nil, err
+	req, err := http.NewRequest(method, url, bodyReader)
+	if err != nil {
+		body.Close()
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+body.boundary)
+	if body.seeker != nil {
+		req.GetBody = body.open
 	}
-	writer.Close()
 
-	// Create the request from our buffer
-	req, err := http.NewRequest(method, url, &buffer)
-	if err != nil {
-		return nil, err
+	return req, body, nil
+}
+
+// multipartBody streams the reader as a single part multipart body
+type multipartBody struct {
+	reader   io.Reader
+	seeker   io.Seeker
+	start    int64
+	boundary string
+
+	mux     sync.Mutex
+	current *io.PipeReader
+	done    chan struct{}
+}
+
+// open starts streaming the body from the start of the reader, stopping any body previously streamed
+func (body *multipartBody) open() (io.ReadCloser, error) {
+	body.mux.Lock()
+	defer body.mux.Unlock()
+
+	body.stop()
+	if body.seeker != nil {
+		if _, err := body.seeker.Seek(body.start, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	bodyReader, bodyWriter := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		writer := multipart.NewWriter(bodyWriter)
+		err := writer.SetBoundary(body.boundary)
+		if err == nil {
+			var partWriter io.Writer
+			partWriter, err = writer.CreatePart(textproto.MIMEHeader{})
+			if err == nil {
+				_, err = io.Copy(partWriter, body.reader)
+			}
+		}
+		if err == nil {
+			err = writer.Close()
+		}
+		bodyWriter.CloseWithError(err)
+	}()
+
+	body.current = bodyReader
+	body.done = done
+	return bodyReader, nil
+}
+
+// Close stops streaming the body, and waits until the reader is no longer being read
+func (body *multipartBody) Close() error {
+	body.mux.Lock()
+	defer body.mux.Unlock()
+
+	body.stop()
+	return nil
+}
+
+func (body *multipartBody) stop() {
+	if body.current == nil {
+		return
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	return req, nil
+	body.current.CloseWithError(io.ErrClosedPipe)
+	<-body.done
+	body.current = nil
+	body.done = nil
 }
 
 func newJSONResponse(httpResponse *http.Response, response interface{}) error {
@@ -6,24 +6,31 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os/exec"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/go-cleanhttp"
 )
 
+// tokens are refreshed this long before they expire so in-flight requests do not fail
+const tokenRefreshWindow = 5 * time.Minute
+
 type tokenResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken string      `json:"access_token"`
+	ExpiresIn   json.Number `json:"expires_in"`
 }
 
 type bearerTokenRoundTripper struct {
 	innerRoundTripper http.RoundTripper
-	getToken          func(*http.Client) (string, error)
+	getToken          func(*http.Client) (*tokenResponse, error)
 	mux               sync.Mutex
 	token             string
+	tokenExpiry       time.Time
 }
 
-func newBearerTokenRoundTripper(getToken func(*http.Client) (string, error), next http.RoundTripper) http.RoundTripper {
+func newBearerTokenRoundTripper(getToken func(*http.Client) (*tokenResponse, error), next http.RoundTripper) http.RoundTripper {
 	return &bearerTokenRoundTripper{
 		innerRoundTripper: next,
 		getToken:          getToken,
@@ -31,105 +38,249 @@ func newBearerTokenRoundTripper(getToken func(*http.Client) (string, error), nex
 }
 
 func (rt *bearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
-	newRequest := *req
+	token, err := rt.acquireToken("")
+	if err != nil {
+		// round trippers must close the body even when the request is never sent
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, err
+	}
 
-	if rt.token == "" {
-		err := func() error {
-			rt.mux.Lock()
-			defer rt.mux.Unlock()
+	resp, err := rt.roundTripWithToken(req, token)
 
-			if rt.token == "" {
+	// the token may have been revoked or expired earlier than advertised, so we get
+	// a new token and replay the request once. Requests with bodies that cannot be
+	// reread cannot be replayed
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized && (req.Body == nil || req.GetBody != nil) {
+		// the rejected response is discarded, so it is closed to release the connection
+		resp.Body.Close()
 
-				// create own http client so we dont try to add token to request to get tokens
-				httpClient := cleanhttp.DefaultClient()
-				httpClient.Transport = newErrorOnUnsuccessfulRoundTripper(httpClient.Transport)
+		token, tokenErr := rt.acquireToken(token)
+		if tokenErr != nil {
+			return nil, tokenErr
+		}
 
-				token, err := rt.getToken(httpClient)
-				if err != nil {
-					return err
-				}
-				rt.token = token
+		replayRequest := *req
+		if req.GetBody != nil {
+			replayRequest.Body, err = req.GetBody()
+			if err != nil {
+				return nil, err
 			}
-			return nil
-		}()
-		if err != nil {
-			return nil, err
 		}
+		resp, err = rt.roundTripWithToken(&replayRequest, token)
 	}
 
-	newRequest.Header.Set("Authorization", "Bearer "+rt.token)
+	return resp, err
+}
+
+func (rt *bearerTokenRoundTripper) roundTripWithToken(req *http.Request, token string) (*http.Response, error) {
+	newRequest := *req
+	newRequest.Header = req.Header.Clone()
+	newRequest.Header.Set("Authorization", "Bearer "+token)
 
 	return rt.innerRoundTripper.RoundTrip(&newRequest)
 }
 
-func getAuthTokenWithPassword(
-	httpClient *http.Client,
-	tenant string,
-	clientID string,
-	clientSecret string,
-	username string,
-	password string,
-) (string, error) {
+// acquireToken returns a token that is not close to expiring. If rejectedToken is
+// provided it will not be returned, unless another request has already replaced it
+func (rt *bearerTokenRoundTripper) acquireToken(rejectedToken string) (string, error) {
+	rt.mux.Lock()
+	defer rt.mux.Unlock()
 
-	authURL := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(tenant))
-	resp, err := httpClient.Post(authURL, "application/x-www-form-urlencoded", strings.NewReader(url.Values{
-		"grant_type":    {"password"},
-		"scope":         {"https://analysis.windows.net/powerbi/api/.default"},
-		"client_id":     {clientID},
-		"client_secret": {clientSecret},
-		"username":      {username},
-		"password":      {password},
-	}.Encode()))
+	isExpiring := !rt.tokenExpiry.IsZero() && time.Now().After(rt.tokenExpiry.Add(-tokenRefreshWindow))
+	if rt.token != "" && rt.token != rejectedToken && !isExpiring {
+		return rt.token, nil
+	}
+
+	// create own http client so we dont try to add token to request to get tokens
+	httpClient := cleanhttp.DefaultClient()
+	httpClient.Transport = newErrorOnUnsuccessfulRoundTripper(httpClient.Transport)
 
+	requested := time.Now()
+	token, err := rt.getToken(httpClient)
 	if err != nil {
 		return "", err
 	}
 
+	rt.token = token.AccessToken
+	rt.tokenExpiry = time.Time{}
+	if expiresIn, err := token.ExpiresIn.Int64(); err == nil && expiresIn > 0 {
+		rt.tokenExpiry = requested.Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return rt.token, nil
+}
+
+func requestToken(httpClient *http.Client, tokenURL string, form url.Values) (*tokenResponse, error) {
+	resp, err := httpClient.Post(tokenURL, "application/x-www-form-urlencoded", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+
+	return readTokenResponse(resp)
+}
+
+func readTokenResponse(resp *http.Response) (*tokenResponse, error) {
 	if resp.StatusCode != 200 {
 		data, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("status: %d, body: %s", resp.StatusCode, data)
+		return nil, fmt.Errorf("status: %d, body: %s", resp.StatusCode, data)
 	}
 
 	data, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	var dataObj tokenResponse
 	err = json.Unmarshal(data, &dataObj)
-	return dataObj.AccessToken, err
+	return &dataObj, err
+}
+
+func getAuthTokenWithPassword(
+	httpClient *http.Client,
+	environment Environment,
+	tenant string,
+	clientID string,
+	clientSecret string,
+	username string,
+	password string,
+) (*tokenResponse, error) {
+
+	return requestToken(httpClient, environment.tokenURL(tenant), url.Values{
+		"grant_type":    {"password"},
+		"scope":         {environment.scope()},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"username":      {username},
+		"password":      {password},
+	})
 }
 
 func getAuthTokenWithClientCredentials(
 	httpClient *http.Client,
+	environment Environment,
 	tenant string,
 	clientID string,
 	clientSecret string,
-) (string, error) {
+) (*tokenResponse, error) {
 
-	authURL := fmt.Sprintf("https://login.microsoftonline.com/%s/oauth2/v2.0/token", url.PathEscape(tenant))
-	resp, err := httpClient.Post(authURL, "application/x-www-form-urlencoded", strings.NewReader(url.Values{
+	return requestToken(httpClient, environment.tokenURL(tenant), url.Values{
 		"grant_type":    {"client_credentials"},
-		"scope":         {"https://analysis.windows.net/powerbi/api/.default"},
+		"scope":         {environment.scope()},
 		"client_id":     {clientID},
 		"client_secret": {clientSecret},
-	}.Encode()))
+	})
+}
+
+func getAuthTokenWithClientAssertion(
+	httpClient *http.Client,
+	environment Environment,
+	tenant string,
+	clientID string,
+	getClientAssertion func() (string, error),
+) (*tokenResponse, error) {
 
+	clientAssertion, err := getClientAssertion()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	if resp.StatusCode != 200 {
-		data, _ := ioutil.ReadAll(resp.Body)
-		return "", fmt.Errorf("status: %d, body: %s", resp.StatusCode, data)
+	return requestToken(httpClient, environment.tokenURL(tenant), url.Values{
+		"grant_type":            {"client_credentials"},
+		"scope":                 {environment.scope()},
+		"client_id":             {clientID},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {clientAssertion},
+	})
+}
+
+func getAuthTokenWithMSI(
+	httpClient *http.Client,
+	environment Environment,
+	msiEndpoint string,
+	clientID string,
+) (*tokenResponse, error) {
+
+	query := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {environment.ResourceID},
+	}
+	// client ID is only required to pick between multiple user assigned identities
+	if clientID != "" {
+		query.Set("client_id", clientID)
 	}
 
-	data, err := ioutil.ReadAll(resp.Body)
+	req, err := http.NewRequest("GET", msiEndpoint+"?"+query.Encode(), nil)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
+	req.Header.Set("Metadata", "true")
 
-	var dataObj tokenResponse
-	err = json.Unmarshal(data, &dataObj)
-	return dataObj.AccessToken, err
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	return readTokenResponse(resp)
+}
+
+// readOIDCToken gets the federated token to exchange for an access token. Token files
+// are read on every exchange as they are typically rotated by the platform
+func readOIDCToken(oidcToken string, oidcTokenFilePath string) (string, error) {
+	if oidcToken != "" {
+		return oidcToken, nil
+	}
+
+	data, err := ioutil.ReadFile(oidcTokenFilePath)
+	if err != nil {
+		return "", fmt.Errorf("Could not read OIDC token file: %s", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// runAzureCLI runs the Azure CLI returning its standard output. Replaced in tests
+var runAzureCLI = func(args ...string) ([]byte, error) {
+	output, err := exec.Command("az", args...).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return nil, fmt.Errorf("Azure CLI failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return output, err
+}
+
+type azureCLITokenResponse struct {
+	AccessToken string
+	// ExpiresOn is in local time, newer versions of the Azure CLI also return expires_on as a unix timestamp
+	ExpiresOn     string
+	ExpiresOnUnix int64 `json:"expires_on"`
+}
+
+func getAuthTokenWithAzureCLI(environment Environment, tenant string) (*tokenResponse, error) {
+	args := []string{"account", "get-access-token", "--resource", environment.ResourceID, "--output", "json"}
+	if tenant != "" {
+		args = append(args, "--tenant", tenant)
+	}
+
+	output, err := runAzureCLI(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var cliToken azureCLITokenResponse
+	if err := json.Unmarshal(output, &cliToken); err != nil {
+		return nil, fmt.Errorf("Could not read Azure CLI access token: %s", err)
+	}
+
+	expiresOn := time.Unix(cliToken.ExpiresOnUnix, 0)
+	if cliToken.ExpiresOnUnix == 0 {
+		expiresOn, err = time.ParseInLocation("2006-01-02 15:04:05.999999", cliToken.ExpiresOn, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("Could not read Azure CLI access token expiry: %s", err)
+		}
+	}
+
+	return &tokenResponse{
+		AccessToken: cliToken.AccessToken,
+		ExpiresIn:   json.Number(fmt.Sprintf("%d", int64(time.Until(expiresOn).Seconds()))),
+	}, nil
 }
@@ -0,0 +1,45 @@
+package powerbiapi
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetAuthTokenWithAzureCLI(t *testing.T) {
+	defer func(original func(args ...string) ([]byte, error)) { runAzureCLI = original }(runAzureCLI)
+
+	expiresOn := time.Now().Add(time.Hour)
+	testCases := []struct {
+		name   string
+		output string
+	}{
+		{"local time expiry", `{"accessToken":"cli-token","expiresOn":"` + expiresOn.Format("2006-01-02 15:04:05.000000") + `","tokenType":"Bearer"}`},
+		{"unix expiry", `{"accessToken":"cli-token","expiresOn":"invalid","expires_on":` + strconv.FormatInt(expiresOn.Unix(), 10) + `}`},
+	}
+
+	for _, testCase := range testCases {
+		var args []string
+		runAzureCLI = func(a ...string) ([]byte, error) {
+			args = a
+			return []byte(testCase.output), nil
+		}
+
+		token, err := getAuthTokenWithAzureCLI(PublicEnvironment, "tenant")
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.name, err)
+		}
+
+		if token.AccessToken != "cli-token" {
+			t.Fatalf("%s: expected access token 'cli-token', found '%s'", testCase.name, token.AccessToken)
+		}
+		expiresIn, err := token.ExpiresIn.Int64()
+		if err != nil || expiresIn < 3500 || expiresIn > 3600 {
+			t.Fatalf("%s: expected token to expire in an hour, found %s", testCase.name, token.ExpiresIn)
+		}
+		if joined := strings.Join(args, " "); joined != "account get-access-token --resource https://analysis.windows.net/powerbi/api --output json --tenant tenant" {
+			t.Fatalf("%s: unexpected Azure CLI arguments '%s'", testCase.name, joined)
+		}
+	}
+}
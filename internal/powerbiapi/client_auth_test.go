@@ -0,0 +1,80 @@
+package powerbiapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+)
+
+func TestBearerToken_reusedUntilExpiry(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetGroups("", -1, 0); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	if issued := server.TokensIssued(); issued != 1 {
+		t.Fatalf("expected 1 token to be issued, found %d", issued)
+	}
+}
+
+func TestBearerToken_refreshedBeforeExpiry(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	// tokens that expire within the refresh window should be refreshed on every request
+	server.TokenLifetime = time.Minute
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetGroups("", -1, 0); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	if issued := server.TokensIssued(); issued != 3 {
+		t.Fatalf("expected 3 tokens to be issued, found %d", issued)
+	}
+}
+
+func TestBearerToken_replayedOnUnauthorized(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := client.GetGroups("", -1, 0); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// requests with a body must be resent with the same body
+	server.ExpireTokens()
+	group, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: "Replayed"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if group.Name != "Replayed" {
+		t.Fatalf("expected group name 'Replayed', found '%s'", group.Name)
+	}
+
+	if issued := server.TokensIssued(); issued != 2 {
+		t.Fatalf("expected 2 tokens to be issued, found %d", issued)
+	}
+}
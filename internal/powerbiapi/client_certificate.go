@@ -0,0 +1,136 @@
+package powerbiapi
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/pkcs12"
+)
+
+// clientCertificate is a certificate and private key used to sign client assertions
+type clientCertificate struct {
+	certificate *x509.Certificate
+	privateKey  *rsa.PrivateKey
+}
+
+// parseClientCertificate reads a certificate and RSA private key from either PEM or PFX data.
+// The password is only used for PFX data
+func parseClientCertificate(data []byte, password string) (*clientCertificate, error) {
+	var blocks []*pem.Block
+	for rest := data; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+
+	// anything that is not PEM is assumed to be PFX
+	if len(blocks) == 0 {
+		var err error
+		blocks, err = pkcs12.ToPEM(data, password)
+		if err != nil {
+			return nil, fmt.Errorf("Could not read certificate as PEM or PFX: %s", err)
+		}
+	}
+
+	var result clientCertificate
+	for _, block := range blocks {
+		switch block.Type {
+		case "CERTIFICATE":
+			certificate, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			// the first certificate is the client certificate, any others are its chain
+			if result.certificate == nil {
+				result.certificate = certificate
+			}
+		case "RSA PRIVATE KEY", "PRIVATE KEY":
+			privateKey, err := parseRSAPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			result.privateKey = privateKey
+		}
+	}
+
+	if result.certificate == nil {
+		return nil, fmt.Errorf("No certificate found in certificate data")
+	}
+	if result.privateKey == nil {
+		return nil, fmt.Errorf("No private key found in certificate data")
+	}
+	return &result, nil
+}
+
+// parseRSAPrivateKey parses PKCS1 or PKCS8 keys. PEM block types cannot be relied on as
+// keys converted from PFX are always labelled PRIVATE KEY even when they are PKCS1
+func parseRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if privateKey, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return privateKey, nil
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("Could not parse certificate private key: %s", err)
+	}
+	rsaPrivateKey, ok := privateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("Certificate private key must be an RSA key")
+	}
+	return rsaPrivateKey, nil
+}
+
+// createClientAssertion creates a JWT signed by the certificate that Azure Active Directory accepts in place of a client secret
+func (cert *clientCertificate) createClientAssertion(tokenURL string, clientID string) (string, error) {
+	thumbprint := sha1.Sum(cert.certificate.Raw)
+	header := map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"x5t": base64.RawURLEncoding.EncodeToString(thumbprint[:]),
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	now := time.Now()
+	claims := map[string]interface{}{
+		"aud": tokenURL,
+		"iss": clientID,
+		"sub": clientID,
+		"jti": fmt.Sprintf("%x", jti),
+		"nbf": now.Unix(),
+		"iat": now.Unix(),
+		"exp": now.Add(10 * time.Minute).Unix(),
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(headerJSON) + "." + base64.RawURLEncoding.EncodeToString(claimsJSON)
+	hash := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, cert.privateKey, crypto.SHA256, hash[:])
+	if err != nil {
+		return "", err
+	}
+
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,92 @@
+package powerbiapi
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func TestParseClientCertificate_formats(t *testing.T) {
+	testCases := []struct {
+		path     string
+		password string
+	}{
+		{"client_certificate_test.pem", ""},
+		{"client_certificate_test.pfx", "password"},
+	}
+
+	for _, testCase := range testCases {
+		data, err := ioutil.ReadFile(testCase.path)
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+
+		certificate, err := parseClientCertificate(data, testCase.password)
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.path, err)
+		}
+		if certificate.certificate.Subject.CommonName != "terraform-provider-powerbi test" {
+			t.Fatalf("%s: unexpected certificate subject '%s'", testCase.path, certificate.certificate.Subject.CommonName)
+		}
+	}
+}
+
+func TestParseClientCertificate_wrongPassword(t *testing.T) {
+	data, err := ioutil.ReadFile("client_certificate_test.pfx")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if _, err := parseClientCertificate(data, "wrong"); err == nil {
+		t.Fatalf("expected an error when the PFX password is wrong")
+	}
+}
+
+func TestClientCertificate_createClientAssertion(t *testing.T) {
+	data, err := ioutil.ReadFile("client_certificate_test.pem")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	certificate, err := parseClientCertificate(data, "")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	tokenURL := PublicEnvironment.tokenURL("tenant")
+	assertion, err := certificate.createClientAssertion(tokenURL, "client")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	parts := strings.Split(assertion, ".")
+	if len(parts) != 3 {
+		t.Fatalf("expected a JWT with 3 parts, found %d", len(parts))
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	hash := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	publicKey := certificate.certificate.PublicKey.(*rsa.PublicKey)
+	if err := rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hash[:], signature); err != nil {
+		t.Fatalf("assertion signature is invalid: %s", err)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var claims map[string]interface{}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if claims["aud"] != tokenURL || claims["iss"] != "client" || claims["sub"] != "client" {
+		t.Fatalf("unexpected assertion claims %v", claims)
+	}
+}
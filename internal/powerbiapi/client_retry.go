@@ -23,6 +23,11 @@ func (rt *retryTooManyRequestsRoundTripper) RoundTrip(req *http.Request) (*http.
 
 retry:
 	for attempts := 1; err == nil && resp.StatusCode == 429; attempts++ {
+		replayRequest, replayable := newReplayRequest(req)
+		if !replayable {
+			break
+		}
+
 		switch attempts {
 		case 1:
 			// retry immediately. PowerBI API typically responds successfully on a retry
@@ -39,12 +44,31 @@ retry:
 			break retry
 		}
 
-		resp, err = rt.innerRoundTripper.RoundTrip(req)
+		resp, err = rt.innerRoundTripper.RoundTrip(replayRequest)
 	}
 
 	return resp, err
 }
 
+// newReplayRequest returns a copy of the request that can be sent again. Requests with bodies
+// that cannot be reread, such as streamed uploads, are not replayable
+func newReplayRequest(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	replayRequest := *req
+	replayRequest.Body = body
+	return &replayRequest, true
+}
+
 func readRetryAfter(resp *http.Response, fallback time.Duration) time.Duration {
 	waitSeconds, parseErr := extractHeaderAsInteger(resp, "Retry-After")
 	if parseErr != nil {
@@ -20,7 +20,12 @@ func (rt *retryIntermittentErrorRoundTripper) RoundTrip(req *http.Request) (*htt
 	resp, err := rt.innerRoundTripper.RoundTrip(req)
 
 retry:
-	for attempts := 1; err == nil && resp.StatusCode == 500 || resp.StatusCode == 400; attempts++ {
+	for attempts := 1; err == nil && (resp.StatusCode == 500 || resp.StatusCode == 400); attempts++ {
+		replayRequest, replayable := newReplayRequest(req)
+		if !replayable {
+			break
+		}
+
 		switch attempts {
 		case 1:
 			// retry immediately. PowerBI API typically responds successfully on a retry
@@ -34,7 +39,7 @@ retry:
 			break retry
 		}
 
-		resp, err = rt.innerRoundTripper.RoundTrip(req)
+		resp, err = rt.innerRoundTripper.RoundTrip(replayRequest)
 	}
 
 	return resp, err
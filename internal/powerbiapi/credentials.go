@@ -0,0 +1,202 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Credentials represents the secrets used to connect to a datasource
+type Credentials struct {
+	CredentialType string
+	Username       string
+	Password       string
+	Key            string
+	AccessToken    string
+}
+
+type credentialDataItem struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+const (
+	// ephemeral keys used to encrypt credentials for gateways with 2048 bit or larger public keys
+	credentialsAESKeyLength  = 32
+	credentialsHMACKeyLength = 64
+
+	// legacy gateways with smaller public keys have the credentials encrypted directly in segments
+	credentialsSegmentLength = 85
+
+	credentialsAlgorithmAES256CBCPKCS7 = 0
+	credentialsAlgorithmHMACSHA256     = 0
+)
+
+// JSON returns the credentials in the format expected by the credential details of datasource requests.
+func (credentials Credentials) JSON() (string, error) {
+	var credentialData []credentialDataItem
+	switch credentials.CredentialType {
+	case "Anonymous":
+		return `{"credentialData":""}`, nil
+	case "Basic", "Windows":
+		if credentials.Username == "" || credentials.Password == "" {
+			return "", fmt.Errorf("username and password are required for %s credentials", credentials.CredentialType)
+		}
+		credentialData = []credentialDataItem{
+			{Name: "username", Value: credentials.Username},
+			{Name: "password", Value: credentials.Password},
+		}
+	case "Key":
+		if credentials.Key == "" {
+			return "", fmt.Errorf("key is required for Key credentials")
+		}
+		credentialData = []credentialDataItem{
+			{Name: "key", Value: credentials.Key},
+		}
+	case "OAuth2":
+		if credentials.AccessToken == "" {
+			return "", fmt.Errorf("access_token is required for OAuth2 credentials")
+		}
+		credentialData = []credentialDataItem{
+			{Name: "accessToken", Value: credentials.AccessToken},
+		}
+	default:
+		return "", fmt.Errorf("Unknown credential type '%s'", credentials.CredentialType)
+	}
+
+	data, err := json.Marshal(struct {
+		CredentialData []credentialDataItem `json:"credentialData"`
+	}{credentialData})
+	return string(data), err
+}
+
+// EncryptCredentials encrypts credentials with the public key of an on-premises gateway, as required by the
+// RSA-OAEP encryption algorithm of datasource requests. The exponent and modulus are base64 encoded as returned by the gateway APIs
+func EncryptCredentials(credentials string, exponent string, modulus string) (string, error) {
+	return encryptCredentials(credentials, exponent, modulus, rand.Reader)
+}
+
+func encryptCredentials(credentials string, exponent string, modulus string, random io.Reader) (string, error) {
+	publicKey, err := parseGatewayPublicKey(exponent, modulus)
+	if err != nil {
+		return "", err
+	}
+
+	var encrypted []byte
+	if publicKey.Size()*8 >= 2048 {
+		encrypted, err = encryptCredentialsWithEphemeralKeys([]byte(credentials), publicKey, random)
+	} else {
+		encrypted, err = encryptCredentialsInSegments([]byte(credentials), publicKey, random)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(encrypted), nil
+}
+
+func parseGatewayPublicKey(exponent string, modulus string) (*rsa.PublicKey, error) {
+	exponentBytes, err := base64.StdEncoding.DecodeString(exponent)
+	if err != nil {
+		return nil, fmt.Errorf("Gateway public key exponent is not valid base64: %s", err)
+	}
+	modulusBytes, err := base64.StdEncoding.DecodeString(modulus)
+	if err != nil {
+		return nil, fmt.Errorf("Gateway public key modulus is not valid base64: %s", err)
+	}
+
+	e := new(big.Int).SetBytes(exponentBytes)
+	if len(modulusBytes) == 0 || !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("Gateway public key is not a valid RSA public key")
+	}
+
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(modulusBytes),
+		E: int(e.Int64()),
+	}, nil
+}
+
+// encryptCredentialsWithEphemeralKeys encrypts the credentials with random AES and HMAC keys, which are themselves
+// encrypted with the public key. The result is the encrypted keys followed by the authenticated ciphertext
+func encryptCredentialsWithEphemeralKeys(credentials []byte, publicKey *rsa.PublicKey, random io.Reader) ([]byte, error) {
+	keys := make([]byte, credentialsAESKeyLength+credentialsHMACKeyLength)
+	if _, err := io.ReadFull(random, keys); err != nil {
+		return nil, err
+	}
+	aesKey := keys[:credentialsAESKeyLength]
+	hmacKey := keys[credentialsAESKeyLength:]
+
+	authenticatedCiphertext, err := authenticatedEncrypt(aesKey, hmacKey, credentials, random)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKeys, err := rsa.EncryptOAEP(sha1.New(), random, publicKey, keys, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(encryptedKeys, authenticatedCiphertext...), nil
+}
+
+// authenticatedEncrypt encrypts the message with AES-256-CBC and signs it with HMAC-SHA256. The result is
+// laid out as the algorithm choices, the tag, the IV and then the ciphertext
+func authenticatedEncrypt(aesKey []byte, hmacKey []byte, message []byte, random io.Reader) ([]byte, error) {
+	block, err := aes.NewCipher(aesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(random, iv); err != nil {
+		return nil, err
+	}
+
+	padding := aes.BlockSize - len(message)%aes.BlockSize
+	plaintext := append(append([]byte{}, message...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	ciphertext := make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+
+	// the algorithm choices and IV are signed along with the ciphertext to prevent tampering
+	algorithmChoices := []byte{credentialsAlgorithmAES256CBCPKCS7, credentialsAlgorithmHMACSHA256}
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(algorithmChoices)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	tag := mac.Sum(nil)
+
+	output := make([]byte, 0, len(algorithmChoices)+len(tag)+len(iv)+len(ciphertext))
+	output = append(output, algorithmChoices...)
+	output = append(output, tag...)
+	output = append(output, iv...)
+	output = append(output, ciphertext...)
+	return output, nil
+}
+
+// encryptCredentialsInSegments encrypts the credentials directly with the public key, a segment at a time
+func encryptCredentialsInSegments(credentials []byte, publicKey *rsa.PublicKey, random io.Reader) ([]byte, error) {
+	var output []byte
+	for start := 0; start < len(credentials); start += credentialsSegmentLength {
+		end := start + credentialsSegmentLength
+		if end > len(credentials) {
+			end = len(credentials)
+		}
+
+		encryptedSegment, err := rsa.EncryptOAEP(sha1.New(), random, publicKey, credentials[start:end], nil)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, encryptedSegment...)
+	}
+	return output, nil
+}
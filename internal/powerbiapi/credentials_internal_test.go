@@ -0,0 +1,158 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+const testBasicCredentials = `{"credentialData":[{"name":"username","value":"user"},{"name":"password","value":"pass"}]}`
+
+// expected output of encrypting testBasicCredentials with the ephemeral keys, IV and OAEP seed from testCredentialsRandom.
+// The ciphertext and tag were produced with openssl enc -aes-256-cbc and openssl dgst -sha256 -mac HMAC
+const testAuthenticatedCiphertext = "00008835fd77bdfdd1698d968ad89e4b3c88b316ec83977019d2d45151a29bb4464e808182838485868788898a8b8c8d8e8fee4ba208aaa4bc4c9c659b022fd8209efd91a5d95de745dcf6ea3ed90ba6193b902b8af92b0087f6e79d2c39f72cbfb5036b9ecc840644cbb2afa08cc567de13a70a6981edbce58bf5fc4ce964d8f2a53fa65519b6a311a9d1813f0a6c511eac"
+const testEncryptedCredentials = "RZlG2qcbXkUbtGmbXf0ayb4NokGOzl7IJyDjMZ47tSrWxzAORlMBhzB7jeyMvpQ5s7pQdxFoftS31FgkNXOyhxkGXpVJZruvISQ1Yy64viACMVcjopu9R4R2ZW6sXOOHfNBo0IhP4qGjjH+DuiV/wnYpZxFoF/eaIU8WL4xvYUoEQ0Ctt02N+GevZg0FIKwlIxUikDjA3Do6Z4D/UgufV3DApVJIRf407CjMBKodsYLgRKgMuleBou3VpTNbzwM9k0T+v6ilKdfRly6mMCwuhrOmDx1IUXBEZlim7u7mS4MsN+sWDb4s87HM+HPWwEO4I1MrKSSSRoGXB6UDPH/nDAAAiDX9d7390WmNlorYnks8iLMW7IOXcBnS1FFRopu0Rk6AgYKDhIWGh4iJiouMjY6P7kuiCKqkvEycZZsCL9ggnv2Rpdld50Xc9uo+2QumGTuQK4r5KwCH9uedLDn3LL+1A2uezIQGRMuyr6CMxWfeE6cKaYHtvOWL9fxM6WTY8qU/plUZtqMRqdGBPwpsUR6s"
+
+func testCredentialsRandom() *bytes.Reader {
+	var random []byte
+	for i := 0; i < 32; i++ {
+		random = append(random, byte(i)) // AES key
+	}
+	for i := 0; i < 64; i++ {
+		random = append(random, byte(0x40+i)) // HMAC key
+	}
+	for i := 0; i < 16; i++ {
+		random = append(random, byte(0x80+i)) // IV
+	}
+	random = append(random, bytes.Repeat([]byte{0xa5}, sha1.Size)...) // OAEP seed
+	return bytes.NewReader(random)
+}
+
+func readTestPrivateKey(t *testing.T, filename string) (*rsa.PrivateKey, string, string) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, _ := pem.Decode(data)
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exponent := base64.StdEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	modulus := base64.StdEncoding.EncodeToString(key.N.Bytes())
+	return key, exponent, modulus
+}
+
+func TestAuthenticatedEncrypt(t *testing.T) {
+	random := testCredentialsRandom()
+	keys := make([]byte, 96)
+	random.Read(keys)
+
+	output, err := authenticatedEncrypt(keys[:32], keys[32:], []byte(testBasicCredentials), random)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hex.EncodeToString(output) != testAuthenticatedCiphertext {
+		t.Fatalf("expected %s, found %s", testAuthenticatedCiphertext, hex.EncodeToString(output))
+	}
+}
+
+func TestEncryptCredentials(t *testing.T) {
+	key, exponent, modulus := readTestPrivateKey(t, "credentials_test_2048.pem")
+
+	encrypted, err := encryptCredentials(testBasicCredentials, exponent, modulus, testCredentialsRandom())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted != testEncryptedCredentials {
+		t.Fatalf("expected %s, found %s", testEncryptedCredentials, encrypted)
+	}
+
+	// the ephemeral keys can be recovered with the private key
+	data, _ := base64.StdEncoding.DecodeString(encrypted)
+	keys, err := rsa.DecryptOAEP(sha1.New(), nil, key, data[:key.Size()], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(keys[:32]) != "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f" || len(keys) != 96 {
+		t.Fatalf("unexpected ephemeral keys %x", keys)
+	}
+	if hex.EncodeToString(data[key.Size():]) != testAuthenticatedCiphertext {
+		t.Fatalf("expected authenticated ciphertext %s, found %x", testAuthenticatedCiphertext, data[key.Size():])
+	}
+}
+
+func TestEncryptCredentials_smallKey(t *testing.T) {
+	key, exponent, modulus := readTestPrivateKey(t, "credentials_test_1024.pem")
+
+	// long enough to be split over two segments
+	credentials := `{"credentialData":[{"name":"key","value":"` + strings.Repeat("k", 100) + `"}]}`
+	encrypted, err := EncryptCredentials(credentials, exponent, modulus)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := base64.StdEncoding.DecodeString(encrypted)
+	if len(data) != 2*key.Size() {
+		t.Fatalf("expected 2 encrypted segments, found %d bytes", len(data))
+	}
+	var decrypted []byte
+	for start := 0; start < len(data); start += key.Size() {
+		segment, err := rsa.DecryptOAEP(sha1.New(), nil, key, data[start:start+key.Size()], nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(segment) > 85 {
+			t.Fatalf("expected segments of at most 85 bytes, found %d", len(segment))
+		}
+		decrypted = append(decrypted, segment...)
+	}
+	if string(decrypted) != credentials {
+		t.Fatalf("expected %s, found %s", credentials, decrypted)
+	}
+}
+
+func TestEncryptCredentials_invalidKey(t *testing.T) {
+	if _, err := EncryptCredentials(testBasicCredentials, "AQAB", "not base64!"); err == nil {
+		t.Fatal("expected an error for an invalid modulus")
+	}
+	if _, err := EncryptCredentials(testBasicCredentials, "", "AQAB"); err == nil {
+		t.Fatal("expected an error for an empty exponent")
+	}
+}
+
+func TestCredentialsJSON(t *testing.T) {
+	testCases := []struct {
+		credentials Credentials
+		expected    string
+	}{
+		{Credentials{CredentialType: "Anonymous"}, `{"credentialData":""}`},
+		{Credentials{CredentialType: "Basic", Username: "user", Password: "pass"}, testBasicCredentials},
+		{Credentials{CredentialType: "Windows", Username: "DOMAIN\\user", Password: "p\"ss"}, `{"credentialData":[{"name":"username","value":"DOMAIN\\user"},{"name":"password","value":"p\"ss"}]}`},
+		{Credentials{CredentialType: "Key", Key: "secret"}, `{"credentialData":[{"name":"key","value":"secret"}]}`},
+		{Credentials{CredentialType: "OAuth2", AccessToken: "token"}, `{"credentialData":[{"name":"accessToken","value":"token"}]}`},
+	}
+
+	for _, testCase := range testCases {
+		actual, err := testCase.credentials.JSON()
+		if err != nil {
+			t.Fatalf("%s: %s", testCase.credentials.CredentialType, err)
+		}
+		if actual != testCase.expected {
+			t.Fatalf("%s: expected %s, found %s", testCase.credentials.CredentialType, testCase.expected, actual)
+		}
+	}
+
+	if _, err := (Credentials{CredentialType: "Basic", Username: "user"}).JSON(); err == nil {
+		t.Fatal("expected an error for Basic credentials without a password")
+	}
+}
@@ -0,0 +1,36 @@
+package powerbiapi
+
+import "net/url"
+
+// GetDataflowsInGroupResponse represents the response from getting the dataflows in a group
+type GetDataflowsInGroupResponse struct {
+	Value []GetDataflowsInGroupResponseItem
+}
+
+// GetDataflowsInGroupResponseItem represents a single dataflow
+type GetDataflowsInGroupResponseItem struct {
+	ObjectID     string
+	Name         string
+	Description  string
+	ModelURL     string
+	ConfiguredBy string
+}
+
+// GetDataflowsInGroup returns a list of dataflows within the specified group
+func (client *Client) GetDataflowsInGroup(groupID string) (*GetDataflowsInGroupResponse, error) {
+
+	var respObj GetDataflowsInGroupResponse
+	url := client.buildURL("/groups/%s/dataflows", url.PathEscape(groupID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// DeleteDataflowInGroup deletes a dataflow that exists within a group
+func (client *Client) DeleteDataflowInGroup(groupID string, dataflowID string) error {
+
+	url := client.buildURL("/groups/%s/dataflows/%s", url.PathEscape(groupID), url.PathEscape(dataflowID))
+	err := client.doJSON("DELETE", url, nil, nil)
+
+	return err
+}
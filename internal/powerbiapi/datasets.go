@@ -3,6 +3,9 @@ package powerbiapi
 import (
 	"fmt"
 	"net/url"
+	"path"
+	"strings"
+	"time"
 )
 
 // GetDatasetInGroupResponse represents the details when getting a datasets in a group.
@@ -14,7 +17,9 @@ type GetDatasetInGroupResponse struct {
 	IsRefreshable                    bool
 	IsEffectiveIdentityRequired      bool
 	IsEffectiveIdentityRolesRequired bool
+	IsOnPremGatewayRequired          bool
 	TargetStorageMode                string
+	WebURL                           string
 }
 
 // GetDatasetsInGroupResponse represents the details when getting a datasets in a group.
@@ -31,7 +36,9 @@ type GetDatasetsInGroupResponseItem struct {
 	IsRefreshable                    bool
 	IsEffectiveIdentityRequired      bool
 	IsEffectiveIdentityRolesRequired bool
+	IsOnPremGatewayRequired          bool
 	TargetStorageMode                string
+	WebURL                           string
 }
 
 // GetParametersInGroupResponse represents the response from get parameters
@@ -47,6 +54,11 @@ type GetParametersInGroupResponseItem struct {
 	CurrentValue string
 }
 
+// UpdateDatasetInGroupRequest represents the request to update the properties of a dataset
+type UpdateDatasetInGroupRequest struct {
+	DisplayName string `json:"displayName,omitempty"`
+}
+
 // UpdateParametersInGroupRequest represents the request to update parameters
 type UpdateParametersInGroupRequest struct {
 	UpdateDetails []UpdateParametersInGroupRequestItem
@@ -75,9 +87,17 @@ type GetDatasourcesInGroupResponseItem struct {
 
 // GetDatasourcesInGroupResponseItemConnectionDetails represents connection details for a single datasource
 type GetDatasourcesInGroupResponseItemConnectionDetails struct {
-	Database *string
-	Server   *string
-	URL      *string
+	Database         *string
+	Server           *string
+	URL              *string
+	Path             *string
+	Kind             *string
+	Domain           *string
+	EmailAddress     *string
+	Account          *string
+	ClassInfo        *string
+	LoginServer      *string
+	ConnectionString *string
 }
 
 // UpdateDatasourcesInGroupRequest represents the request to update datasources
@@ -95,13 +115,50 @@ type UpdateDatasourcesInGroupRequestItem struct {
 type UpdateDatasourcesInGroupRequestItemDatasourceSelector struct {
 	DatasourceType    string
 	ConnectionDetails UpdateDatasourcesInGroupRequestItemConnectionDetails
+	DatasourceID      string `json:",omitempty"`
+	GatewayID         string `json:",omitempty"`
 }
 
 // UpdateDatasourcesInGroupRequestItemConnectionDetails represents connection details for a single datasource
 type UpdateDatasourcesInGroupRequestItemConnectionDetails struct {
-	Database *string
-	Server   *string
-	URL      *string
+	Database         *string
+	Server           *string
+	URL              *string
+	Path             *string
+	Kind             *string
+	Domain           *string
+	EmailAddress     *string
+	Account          *string
+	ClassInfo        *string
+	LoginServer      *string
+	ConnectionString *string
+}
+
+// BindToGatewayInGroupRequest represents the request to bind a dataset to a gateway
+type BindToGatewayInGroupRequest struct {
+	GatewayObjectID     string   `json:"gatewayObjectId"`
+	DatasourceObjectIDs []string `json:"datasourceObjectIds,omitempty"`
+}
+
+// DiscoverGatewaysInGroupResponse represents the gateways a dataset can be bound to
+type DiscoverGatewaysInGroupResponse struct {
+	Value []DiscoverGatewaysInGroupResponseItem
+}
+
+// DiscoverGatewaysInGroupResponseItem represents a single gateway a dataset can be bound to
+type DiscoverGatewaysInGroupResponseItem struct {
+	ID                string
+	Name              string
+	Type              string
+	PublicKey         DiscoverGatewaysInGroupResponseItemPublicKey
+	GatewayAnnotation string
+	GatewayStatus     string
+}
+
+// DiscoverGatewaysInGroupResponseItemPublicKey represents the public key used to encrypt credentials for a gateway
+type DiscoverGatewaysInGroupResponseItemPublicKey struct {
+	Exponent string
+	Modulus  string
 }
 
 // GetRefreshScheduleInGroupResponse represents the response to getting a refresh schedule
@@ -127,11 +184,111 @@ type UpdateRefreshScheduleInGroupRequestValue struct {
 	NotifyOption    *string   `json:"notifyOption,omitempty"`
 }
 
+// GetDirectQueryRefreshScheduleInGroupResponse represents the response to getting a DirectQuery or LiveConnection refresh schedule
+type GetDirectQueryRefreshScheduleInGroupResponse struct {
+	Frequency       int
+	Days            []string
+	Times           []string
+	LocalTimeZoneID string
+}
+
+// UpdateDirectQueryRefreshScheduleInGroupRequest represents the request to update a DirectQuery or LiveConnection refresh schedule
+type UpdateDirectQueryRefreshScheduleInGroupRequest struct {
+	Value UpdateDirectQueryRefreshScheduleInGroupRequestValue `json:"value"`
+}
+
+// UpdateDirectQueryRefreshScheduleInGroupRequestValue represents the value section in the request to update a DirectQuery or LiveConnection refresh schedule
+type UpdateDirectQueryRefreshScheduleInGroupRequestValue struct {
+	Frequency       *int      `json:"frequency,omitempty"`
+	Days            *[]string `json:"days,omitempty"`
+	Times           *[]string `json:"times,omitempty"`
+	LocalTimeZoneID *string   `json:"localTimeZoneId,omitempty"`
+}
+
+// RefreshDatasetInGroupRequest represents the request to refresh a dataset
+type RefreshDatasetInGroupRequest struct {
+	NotifyOption string `json:"notifyOption,omitempty"`
+}
+
+// RefreshDatasetInGroupResponse represents the response from refreshing a dataset
+type RefreshDatasetInGroupResponse struct {
+	RequestID string
+	// RequestedTime is when the refresh was requested, which identifies the refresh if the service does not return a request ID
+	RequestedTime time.Time
+}
+
+// GetRefreshHistoryInGroupResponse represents the response from getting the refresh history of a dataset
+type GetRefreshHistoryInGroupResponse struct {
+	Value []GetRefreshHistoryInGroupResponseItem
+}
+
+// GetRefreshHistoryInGroupResponseItem represents a single refresh of a dataset
+type GetRefreshHistoryInGroupResponseItem struct {
+	RequestID            string
+	ID                   int64
+	RefreshType          string
+	StartTime            time.Time
+	EndTime              time.Time
+	Status               string
+	ServiceExceptionJSON string
+}
+
+// EnhancedRefreshDatasetInGroupRequest represents the request to refresh specific tables and partitions of a Premium dataset
+type EnhancedRefreshDatasetInGroupRequest struct {
+	Type               string                                       `json:"type,omitempty"`
+	CommitMode         string                                       `json:"commitMode,omitempty"`
+	MaxParallelism     int                                          `json:"maxParallelism,omitempty"`
+	RetryCount         int                                          `json:"retryCount,omitempty"`
+	Objects            []EnhancedRefreshDatasetInGroupRequestObject `json:"objects,omitempty"`
+	ApplyRefreshPolicy *bool                                        `json:"applyRefreshPolicy,omitempty"`
+	EffectiveDate      string                                       `json:"effectiveDate,omitempty"`
+}
+
+// EnhancedRefreshDatasetInGroupRequestObject represents a table, or a partition within a table, to refresh
+type EnhancedRefreshDatasetInGroupRequestObject struct {
+	Table     string `json:"table"`
+	Partition string `json:"partition,omitempty"`
+}
+
+// EnhancedRefreshDatasetInGroupResponse represents the response from starting an enhanced refresh
+type EnhancedRefreshDatasetInGroupResponse struct {
+	RequestID string
+	RefreshID string
+}
+
+// GetRefreshExecutionDetailsInGroupResponse represents the progress of an enhanced refresh
+type GetRefreshExecutionDetailsInGroupResponse struct {
+	StartTime          time.Time
+	EndTime            time.Time
+	Type               string
+	CommitMode         string
+	Status             string
+	ExtendedStatus     string
+	CurrentRefreshType string
+	NumberOfAttempts   int
+	Objects            []GetRefreshExecutionDetailsInGroupResponseObject
+	Messages           []GetRefreshExecutionDetailsInGroupResponseMessage
+}
+
+// GetRefreshExecutionDetailsInGroupResponseObject represents the progress of a single table or partition
+type GetRefreshExecutionDetailsInGroupResponseObject struct {
+	Table     string
+	Partition string
+	Status    string
+}
+
+// GetRefreshExecutionDetailsInGroupResponseMessage represents an error or warning raised by an enhanced refresh
+type GetRefreshExecutionDetailsInGroupResponseMessage struct {
+	Code    string
+	Message string
+	Type    string
+}
+
 // GetDatasetInGroup returns a dataset within the specified group.
 func (client *Client) GetDatasetInGroup(groupID string, datasetID string) (*GetDatasetInGroupResponse, error) {
 
 	var respObj GetDatasetInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -141,7 +298,7 @@ func (client *Client) GetDatasetInGroup(groupID string, datasetID string) (*GetD
 func (client *Client) GetDatasetsInGroup(groupID string) (*GetDatasetsInGroupResponse, error) {
 
 	var respObj GetDatasetsInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets", url.PathEscape(groupID))
+	url := client.buildURL("/groups/%s/datasets", url.PathEscape(groupID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -150,17 +307,30 @@ func (client *Client) GetDatasetsInGroup(groupID string) (*GetDatasetsInGroupRes
 // DeleteDatasetInGroup deletes a dataset that exists within a group.
 func (client *Client) DeleteDatasetInGroup(groupID string, datasetID string) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("DELETE", url, nil, nil)
 
 	return err
 }
 
+// UpdateDatasetInGroup updates the properties of a dataset that exists within a group. Power BI cannot rename
+// datasets so this uses the Fabric semantic model API.
+func (client *Client) UpdateDatasetInGroup(groupID string, datasetID string, request UpdateDatasetInGroupRequest) error {
+
+	url, err := client.buildFabricURL("/workspaces/%s/semanticModels/%s", url.PathEscape(groupID), url.PathEscape(datasetID))
+	if err != nil {
+		return err
+	}
+	err = client.doJSON("PATCH", url, &request, nil)
+
+	return err
+}
+
 // GetParametersInGroup gets parameters in a dataset that exists within a group.
 func (client *Client) GetParametersInGroup(groupID string, datasetID string) (*GetParametersInGroupResponse, error) {
 
 	var respObj GetParametersInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/parameters", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s/parameters", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -169,7 +339,7 @@ func (client *Client) GetParametersInGroup(groupID string, datasetID string) (*G
 // UpdateParametersInGroup updates parameters in a dataset that exists within a group.
 func (client *Client) UpdateParametersInGroup(groupID string, datasetID string, request UpdateParametersInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/Default.UpdateParameters", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s/Default.UpdateParameters", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("POST", url, &request, nil)
 
 	return err
@@ -179,7 +349,7 @@ func (client *Client) UpdateParametersInGroup(groupID string, datasetID string,
 func (client *Client) GetDatasourcesInGroup(groupID string, datasetID string) (*GetDatasourcesInGroupResponse, error) {
 
 	var respObj GetDatasourcesInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/datasources", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s/datasources", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -188,17 +358,45 @@ func (client *Client) GetDatasourcesInGroup(groupID string, datasetID string) (*
 // UpdateDatasourcesInGroup updates datasources in a dataset that exists within a group.
 func (client *Client) UpdateDatasourcesInGroup(groupID string, datasetID string, request UpdateDatasourcesInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/Default.UpdateDatasources", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s/Default.UpdateDatasources", url.PathEscape(groupID), url.PathEscape(datasetID))
+	err := client.doJSON("POST", url, &request, nil)
+
+	return err
+}
+
+// TakeOverInGroup transfers ownership of a dataset to the current user or service principal.
+func (client *Client) TakeOverInGroup(groupID string, datasetID string) error {
+
+	url := client.buildURL("/groups/%s/datasets/%s/Default.TakeOver", url.PathEscape(groupID), url.PathEscape(datasetID))
+	err := client.doJSON("POST", url, nil, nil)
+
+	return err
+}
+
+// BindToGatewayInGroup binds a dataset to a gateway. If no datasource IDs are provided the gateway datasources are matched by connection details.
+func (client *Client) BindToGatewayInGroup(groupID string, datasetID string, request BindToGatewayInGroupRequest) error {
+
+	url := client.buildURL("/groups/%s/datasets/%s/Default.BindToGateway", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("POST", url, &request, nil)
 
 	return err
 }
 
+// DiscoverGatewaysInGroup returns the gateways a dataset can be bound to.
+func (client *Client) DiscoverGatewaysInGroup(groupID string, datasetID string) (*DiscoverGatewaysInGroupResponse, error) {
+
+	var respObj DiscoverGatewaysInGroupResponse
+	url := client.buildURL("/groups/%s/datasets/%s/Default.DiscoverGateways", url.PathEscape(groupID), url.PathEscape(datasetID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
 // GetRefreshScheduleInGroup gets a datasource's refresh schedule.
 func (client *Client) GetRefreshScheduleInGroup(groupID string, datasetID string) (*GetRefreshScheduleInGroupResponse, error) {
 
 	var respObj GetRefreshScheduleInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/refreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s/refreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -207,8 +405,195 @@ func (client *Client) GetRefreshScheduleInGroup(groupID string, datasetID string
 // UpdateRefreshScheduleInGroup updates a datasource's refresh schedule.
 func (client *Client) UpdateRefreshScheduleInGroup(groupID string, datasetID string, request UpdateRefreshScheduleInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/refreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
+	url := client.buildURL("/groups/%s/datasets/%s/refreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
 	err := client.doJSON("PATCH", url, &request, nil)
 
 	return err
 }
+
+// GetDirectQueryRefreshScheduleInGroup gets a DirectQuery or LiveConnection dataset's refresh schedule.
+func (client *Client) GetDirectQueryRefreshScheduleInGroup(groupID string, datasetID string) (*GetDirectQueryRefreshScheduleInGroupResponse, error) {
+
+	var respObj GetDirectQueryRefreshScheduleInGroupResponse
+	url := client.buildURL("/groups/%s/datasets/%s/directQueryRefreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// UpdateDirectQueryRefreshScheduleInGroup updates a DirectQuery or LiveConnection dataset's refresh schedule.
+func (client *Client) UpdateDirectQueryRefreshScheduleInGroup(groupID string, datasetID string, request UpdateDirectQueryRefreshScheduleInGroupRequest) error {
+
+	url := client.buildURL("/groups/%s/datasets/%s/directQueryRefreshSchedule", url.PathEscape(groupID), url.PathEscape(datasetID))
+	err := client.doJSON("PATCH", url, &request, nil)
+
+	return err
+}
+
+// RefreshDatasetInGroup triggers a refresh of a dataset. The refresh runs asynchronously, use WaitForRefreshInGroupToComplete to wait for it to finish.
+func (client *Client) RefreshDatasetInGroup(groupID string, datasetID string, request RefreshDatasetInGroupRequest) (*RefreshDatasetInGroupResponse, error) {
+
+	url := client.buildURL("/groups/%s/datasets/%s/refreshes", url.PathEscape(groupID), url.PathEscape(datasetID))
+	httpRequest, err := newJSONRequest("POST", url, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	requestedTime := time.Now()
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	// the refresh is only identified by the request ID header of the accepted response
+	return &RefreshDatasetInGroupResponse{
+		RequestID:     httpResponse.Header.Get("RequestId"),
+		RequestedTime: requestedTime,
+	}, nil
+}
+
+// GetRefreshHistoryInGroup returns the most recent refreshes of a dataset, newest first. If top is 0 the service default is used.
+func (client *Client) GetRefreshHistoryInGroup(groupID string, datasetID string, top int) (*GetRefreshHistoryInGroupResponse, error) {
+
+	queryParams := url.Values{}
+	if top > 0 {
+		queryParams.Add("$top", fmt.Sprintf("%d", top))
+	}
+
+	var respObj GetRefreshHistoryInGroupResponse
+	url := client.buildURL("/groups/%s/datasets/%s/refreshes?%s", url.PathEscape(groupID), url.PathEscape(datasetID), queryParams.Encode())
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// EnhancedRefreshDatasetInGroup starts an enhanced refresh of a Premium dataset. The refresh runs asynchronously,
+// use WaitForEnhancedRefreshInGroupToComplete to wait for it to finish.
+func (client *Client) EnhancedRefreshDatasetInGroup(groupID string, datasetID string, request EnhancedRefreshDatasetInGroupRequest) (*EnhancedRefreshDatasetInGroupResponse, error) {
+
+	url := client.buildURL("/groups/%s/datasets/%s/refreshes", url.PathEscape(groupID), url.PathEscape(datasetID))
+	httpRequest, err := newJSONRequest("POST", url, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	httpResponse, err := client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResponse.Body.Close()
+
+	// the location of the accepted response points at the refresh execution details
+	location, err := httpResponse.Location()
+	if err != nil {
+		return nil, fmt.Errorf("Enhanced refresh response did not include the refresh location: %s", err)
+	}
+
+	return &EnhancedRefreshDatasetInGroupResponse{
+		RequestID: httpResponse.Header.Get("RequestId"),
+		RefreshID: path.Base(location.Path),
+	}, nil
+}
+
+// GetRefreshExecutionDetailsInGroup returns the progress of an enhanced refresh.
+func (client *Client) GetRefreshExecutionDetailsInGroup(groupID string, datasetID string, refreshID string) (*GetRefreshExecutionDetailsInGroupResponse, error) {
+
+	var respObj GetRefreshExecutionDetailsInGroupResponse
+	url := client.buildURL("/groups/%s/datasets/%s/refreshes/%s", url.PathEscape(groupID), url.PathEscape(datasetID), url.PathEscape(refreshID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// CancelRefreshInGroup cancels an in progress enhanced refresh.
+func (client *Client) CancelRefreshInGroup(groupID string, datasetID string, refreshID string) error {
+
+	url := client.buildURL("/groups/%s/datasets/%s/refreshes/%s", url.PathEscape(groupID), url.PathEscape(datasetID), url.PathEscape(refreshID))
+	err := client.doJSON("DELETE", url, nil, nil)
+
+	return err
+}
+
+// WaitForEnhancedRefreshInGroupToComplete waits until the specified enhanced refresh completes.
+// If the wait times out the last execution details are returned along with the error
+func (client *Client) WaitForEnhancedRefreshInGroupToComplete(groupID string, datasetID string, refreshID string, timeout time.Duration) (*GetRefreshExecutionDetailsInGroupResponse, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	started := time.Now()
+	for {
+		details, err := client.GetRefreshExecutionDetailsInGroup(groupID, datasetID, refreshID)
+		if err != nil {
+			return nil, err
+		}
+
+		switch details.Status {
+		case "Completed":
+			return details, nil
+		case "Unknown", "NotStarted", "InProgress":
+			// still running
+		default:
+			var messages []string
+			for _, message := range details.Messages {
+				messages = append(messages, fmt.Sprintf("%s: %s", message.Code, message.Message))
+			}
+			if len(messages) > 0 {
+				return details, fmt.Errorf("Refresh completed with status '%s': %s", details.Status, strings.Join(messages, "; "))
+			}
+			return details, fmt.Errorf("Refresh completed with status '%s'", details.Status)
+		}
+
+		now := <-ticker.C
+		if now.Sub(started) > timeout {
+			return details, fmt.Errorf("Timed out waiting for refresh to complete. Refresh taking longer than %v seconds", timeout.Seconds())
+		}
+	}
+}
+
+// WaitForRefreshInGroupToComplete waits until the requested refresh of a dataset completes.
+// If the request ID is not known the first refresh started after it was requested is waited on
+func (client *Client) WaitForRefreshInGroupToComplete(groupID string, datasetID string, requested *RefreshDatasetInGroupResponse, timeout time.Duration) (*GetRefreshHistoryInGroupResponseItem, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	started := time.Now()
+	for {
+		history, err := client.GetRefreshHistoryInGroup(groupID, datasetID, 10)
+		if err != nil {
+			return nil, err
+		}
+
+		// a refresh may not appear in the history immediately after it has been requested, so earlier
+		// refreshes are never waited on. The history is newest first
+		var refresh *GetRefreshHistoryInGroupResponseItem
+		for i := range history.Value {
+			if requested.RequestID != "" && history.Value[i].RequestID == requested.RequestID {
+				refresh = &history.Value[i]
+				break
+			}
+			if requested.RequestID == "" && history.Value[i].StartTime.After(requested.RequestedTime) {
+				refresh = &history.Value[i]
+			}
+		}
+
+		if refresh != nil {
+			switch refresh.Status {
+			case "Completed":
+				return refresh, nil
+			case "Unknown", "NotStarted", "InProgress":
+				// still running
+			default:
+				if refresh.ServiceExceptionJSON != "" {
+					return refresh, fmt.Errorf("Refresh completed with status '%s': %s", refresh.Status, refresh.ServiceExceptionJSON)
+				}
+				return refresh, fmt.Errorf("Refresh completed with status '%s'", refresh.Status)
+			}
+		}
+
+		now := <-ticker.C
+		if now.Sub(started) > timeout {
+			return nil, fmt.Errorf("Timed out waiting for refresh to complete. Refresh taking longer than %v seconds", timeout.Seconds())
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package powerbiapi
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Environment represents the endpoints of a Power BI cloud
+type Environment struct {
+	// APIEndpoint is the root of the Power BI REST API, excluding the /v1.0/myorg path
+	APIEndpoint string
+	// AuthorityHost is the Azure Active Directory host tokens are requested from
+	AuthorityHost string
+	// ResourceID is the Power BI resource tokens are requested for
+	ResourceID string
+	// FabricAPIEndpoint is the root of the Fabric REST API, excluding the /v1 path. Only required for the
+	// operations Power BI has no API for, such as renaming reports and datasets
+	FabricAPIEndpoint string
+}
+
+var environments = map[string]Environment{
+	"public": {
+		APIEndpoint:       "https://api.powerbi.com",
+		AuthorityHost:     "https://login.microsoftonline.com",
+		ResourceID:        "https://analysis.windows.net/powerbi/api",
+		FabricAPIEndpoint: "https://api.fabric.microsoft.com",
+	},
+	"usgov": {
+		APIEndpoint:   "https://api.powerbigov.us",
+		AuthorityHost: "https://login.microsoftonline.com",
+		ResourceID:    "https://analysis.usgovcloudapi.net/powerbi/api",
+	},
+	"usgovhigh": {
+		APIEndpoint:   "https://api.high.powerbigov.us",
+		AuthorityHost: "https://login.microsoftonline.us",
+		ResourceID:    "https://high.analysis.usgovcloudapi.net/powerbi/api",
+	},
+	"dod": {
+		APIEndpoint:   "https://api.mil.powerbigov.us",
+		AuthorityHost: "https://login.microsoftonline.us",
+		ResourceID:    "https://mil.analysis.usgovcloudapi.net/powerbi/api",
+	},
+	"china": {
+		APIEndpoint:   "https://api.powerbi.cn",
+		AuthorityHost: "https://login.chinacloudapi.cn",
+		ResourceID:    "https://analysis.chinacloudapi.cn/powerbi/api",
+	},
+}
+
+// DefaultMSIEndpoint is the Azure Instance Metadata Service endpoint that managed identity tokens are requested from
+const DefaultMSIEndpoint = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// PublicEnvironment is the global Power BI cloud
+var PublicEnvironment = environments["public"]
+
+// EnvironmentNames returns the names of all known Power BI clouds
+func EnvironmentNames() []string {
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetEnvironment returns the endpoints for a named Power BI cloud
+func GetEnvironment(name string) (Environment, error) {
+	environment, ok := environments[strings.ToLower(name)]
+	if !ok {
+		return Environment{}, fmt.Errorf("Unknown environment '%s'. Expected one of %s", name, strings.Join(EnvironmentNames(), ", "))
+	}
+	return environment, nil
+}
+
+func (environment Environment) tokenURL(tenant string) string {
+	return fmt.Sprintf("%s/%s/oauth2/v2.0/token", strings.TrimRight(environment.AuthorityHost, "/"), url.PathEscape(tenant))
+}
+
+func (environment Environment) scope() string {
+	return strings.TrimRight(environment.ResourceID, "/") + "/.default"
+}
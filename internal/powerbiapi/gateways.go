@@ -0,0 +1,199 @@
+package powerbiapi
+
+import (
+	"net/url"
+)
+
+// GetGatewaysResponse represents the response to getting the gateways the user is an admin of
+type GetGatewaysResponse struct {
+	Value []GetGatewaysResponseItem
+}
+
+// GetGatewaysResponseItem represents a single gateway
+type GetGatewaysResponseItem struct {
+	ID                string
+	Name              string
+	Type              string
+	PublicKey         GetGatewaysResponseItemPublicKey
+	GatewayAnnotation string
+	GatewayStatus     string
+}
+
+// GetGatewaysResponseItemPublicKey represents the public key used to encrypt credentials for a gateway
+type GetGatewaysResponseItemPublicKey struct {
+	Exponent string
+	Modulus  string
+}
+
+// GetGatewayResponse represents the response to getting a single gateway
+type GetGatewayResponse GetGatewaysResponseItem
+
+// GetGatewayDatasourcesResponse represents the response to getting the datasources of a gateway
+type GetGatewayDatasourcesResponse struct {
+	Value []GetGatewayDatasourcesResponseItem
+}
+
+// GetGatewayDatasourcesResponseItem represents a single datasource on a gateway
+type GetGatewayDatasourcesResponseItem struct {
+	ID                string
+	GatewayID         string
+	DatasourceType    string
+	DatasourceName    string
+	ConnectionDetails string
+	CredentialType    string
+}
+
+// GetGatewayDatasourceResponse represents the response to getting a single datasource on a gateway
+type GetGatewayDatasourceResponse GetGatewayDatasourcesResponseItem
+
+// CreateGatewayDatasourceRequest represents the request to create a datasource on a gateway
+type CreateGatewayDatasourceRequest struct {
+	DataSourceType    string                                          `json:"dataSourceType"`
+	ConnectionDetails string                                          `json:"connectionDetails"`
+	DatasourceName    string                                          `json:"datasourceName"`
+	CredentialDetails CreateGatewayDatasourceRequestCredentialDetails `json:"credentialDetails"`
+}
+
+// CreateGatewayDatasourceRequestCredentialDetails represents the credentials of a datasource being created on a gateway
+type CreateGatewayDatasourceRequestCredentialDetails struct {
+	CredentialType      string `json:"credentialType"`
+	Credentials         string `json:"credentials"`
+	EncryptedConnection string `json:"encryptedConnection"`
+	EncryptionAlgorithm string `json:"encryptionAlgorithm"`
+	PrivacyLevel        string `json:"privacyLevel"`
+}
+
+// CreateGatewayDatasourceResponse represents the response to creating a datasource on a gateway
+type CreateGatewayDatasourceResponse GetGatewayDatasourcesResponseItem
+
+// UpdateGatewayDatasourceRequest represents the request to update the credentials of a datasource on a gateway
+type UpdateGatewayDatasourceRequest struct {
+	CredentialDetails UpdateGatewayDatasourceRequestCredentialDetails `json:"credentialDetails"`
+}
+
+// UpdateGatewayDatasourceRequestCredentialDetails represents the new credentials of a datasource on a gateway
+type UpdateGatewayDatasourceRequestCredentialDetails struct {
+	CredentialType      string `json:"credentialType"`
+	Credentials         string `json:"credentials"`
+	EncryptedConnection string `json:"encryptedConnection"`
+	EncryptionAlgorithm string `json:"encryptionAlgorithm"`
+	PrivacyLevel        string `json:"privacyLevel"`
+}
+
+// GetGatewayDatasourceUsersResponse represents the response to getting the users of a datasource on a gateway
+type GetGatewayDatasourceUsersResponse struct {
+	Value []GetGatewayDatasourceUsersResponseItem
+}
+
+// GetGatewayDatasourceUsersResponseItem represents a single user of a datasource on a gateway
+type GetGatewayDatasourceUsersResponseItem struct {
+	DatasourceAccessRight string
+	DisplayName           string
+	EmailAddress          string
+	Identifier            string
+	PrincipalType         string
+}
+
+// AddGatewayDatasourceUserRequest represents the request to grant a user access to a datasource on a gateway
+type AddGatewayDatasourceUserRequest struct {
+	DatasourceAccessRight string `json:"datasourceAccessRight"`
+	DisplayName           string `json:"displayName,omitempty"`
+	EmailAddress          string `json:"emailAddress,omitempty"`
+	Identifier            string `json:"identifier,omitempty"`
+	PrincipalType         string `json:"principalType,omitempty"`
+}
+
+// GetGateways returns the gateways the user is an admin of.
+func (client *Client) GetGateways() (*GetGatewaysResponse, error) {
+
+	var respObj GetGatewaysResponse
+	err := client.doJSON("GET", client.buildURL("/gateways"), nil, &respObj)
+
+	return &respObj, err
+}
+
+// GetGateway returns a single gateway the user is an admin of.
+func (client *Client) GetGateway(gatewayID string) (*GetGatewayResponse, error) {
+
+	var respObj GetGatewayResponse
+	url := client.buildURL("/gateways/%s", url.PathEscape(gatewayID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// GetGatewayDatasources returns the datasources configured on a gateway.
+func (client *Client) GetGatewayDatasources(gatewayID string) (*GetGatewayDatasourcesResponse, error) {
+
+	var respObj GetGatewayDatasourcesResponse
+	url := client.buildURL("/gateways/%s/datasources", url.PathEscape(gatewayID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// GetGatewayDatasource returns a single datasource configured on a gateway.
+func (client *Client) GetGatewayDatasource(gatewayID string, datasourceID string) (*GetGatewayDatasourceResponse, error) {
+
+	var respObj GetGatewayDatasourceResponse
+	url := client.buildURL("/gateways/%s/datasources/%s", url.PathEscape(gatewayID), url.PathEscape(datasourceID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// CreateGatewayDatasource creates a datasource on a gateway.
+func (client *Client) CreateGatewayDatasource(gatewayID string, request CreateGatewayDatasourceRequest) (*CreateGatewayDatasourceResponse, error) {
+
+	var respObj CreateGatewayDatasourceResponse
+	url := client.buildURL("/gateways/%s/datasources", url.PathEscape(gatewayID))
+	err := client.doJSON("POST", url, &request, &respObj)
+
+	return &respObj, err
+}
+
+// UpdateGatewayDatasource updates the credentials of a datasource on a gateway.
+func (client *Client) UpdateGatewayDatasource(gatewayID string, datasourceID string, request UpdateGatewayDatasourceRequest) error {
+
+	url := client.buildURL("/gateways/%s/datasources/%s", url.PathEscape(gatewayID), url.PathEscape(datasourceID))
+	err := client.doJSON("PATCH", url, &request, nil)
+
+	return err
+}
+
+// DeleteGatewayDatasource deletes a datasource from a gateway.
+func (client *Client) DeleteGatewayDatasource(gatewayID string, datasourceID string) error {
+
+	url := client.buildURL("/gateways/%s/datasources/%s", url.PathEscape(gatewayID), url.PathEscape(datasourceID))
+	err := client.doJSON("DELETE", url, nil, nil)
+
+	return err
+}
+
+// GetGatewayDatasourceUsers returns the users that have access to a datasource on a gateway.
+func (client *Client) GetGatewayDatasourceUsers(gatewayID string, datasourceID string) (*GetGatewayDatasourceUsersResponse, error) {
+
+	var respObj GetGatewayDatasourceUsersResponse
+	url := client.buildURL("/gateways/%s/datasources/%s/users", url.PathEscape(gatewayID), url.PathEscape(datasourceID))
+	err := client.doJSON("GET", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+// AddGatewayDatasourceUser grants or updates a user's access to a datasource on a gateway.
+func (client *Client) AddGatewayDatasourceUser(gatewayID string, datasourceID string, request AddGatewayDatasourceUserRequest) error {
+
+	url := client.buildURL("/gateways/%s/datasources/%s/users", url.PathEscape(gatewayID), url.PathEscape(datasourceID))
+	err := client.doJSON("POST", url, &request, nil)
+
+	return err
+}
+
+// DeleteGatewayDatasourceUser removes a user's access to a datasource on a gateway. The user is identified by email address or, for groups and apps, by identifier.
+func (client *Client) DeleteGatewayDatasourceUser(gatewayID string, datasourceID string, userInfo string) error {
+
+	url := client.buildURL("/gateways/%s/datasources/%s/users/%s", url.PathEscape(gatewayID), url.PathEscape(datasourceID), url.PathEscape(userInfo))
+	err := client.doJSON("DELETE", url, nil, nil)
+
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 // CreateGroupRequest represents the request for the CreateGroup API
@@ -76,7 +77,7 @@ type UpdateGroupUserRequest struct {
 func (client *Client) CreateGroup(request CreateGroupRequest) (*CreateGroupResponse, error) {
 
 	var respObj CreateGroupResponse
-	err := client.doJSON("POST", "https://api.powerbi.com/v1.0/myorg/groups?workspaceV2=True", request, &respObj)
+	err := client.doJSON("POST", client.buildURL("/groups?workspaceV2=True"), request, &respObj)
 	return &respObj, err
 }
 
@@ -95,11 +96,28 @@ func (client *Client) GetGroups(filter string, top int, skip int) (*GetGroupsRes
 	}
 
 	var respObj GetGroupsResponse
-	err := client.doJSON("GET", "https://api.powerbi.com/v1.0/myorg/groups?"+queryParams.Encode(), nil, &respObj)
+	err := client.doJSON("GET", client.buildURL("/groups?%s", queryParams.Encode()), nil, &respObj)
 
 	return &respObj, err
 }
 
+// GetAllGroups returns every workspace the user has access to that matches the filter, requesting pageSize workspaces at a time
+func (client *Client) GetAllGroups(filter string, pageSize int) ([]GetGroupsResponseItem, error) {
+
+	result := make([]GetGroupsResponseItem, 0)
+	for skip := 0; ; skip += pageSize {
+		groups, err := client.GetGroups(filter, pageSize, skip)
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, groups.Value...)
+		if len(groups.Value) < pageSize {
+			return result, nil
+		}
+	}
+}
+
 // GetGroup returns a single workspace
 func (client *Client) GetGroup(groupID string) (*GetGroupResponse, error) {
 
@@ -129,7 +147,7 @@ func (client *Client) GetGroupByName(groupName string) (*GetGroupResponse, error
 
 	// There is no endpoint to get a single workspace, so we will search for
 	// all workspaces with a specific name
-	groups, err := client.GetGroups(fmt.Sprintf("name eq '%s'", groupName), -1, 0)
+	groups, err := client.GetGroups(fmt.Sprintf("name eq '%s'", escapeODataString(groupName)), -1, 0)
 
 	if err != nil {
 		return nil, err
@@ -150,7 +168,7 @@ func (client *Client) GetGroupByName(groupName string) (*GetGroupResponse, error
 
 // DeleteGroup deletes a workspace
 func (client *Client) DeleteGroup(groupID string) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s", url.PathEscape(groupID))
+	url := client.buildURL("/groups/%s", url.PathEscape(groupID))
 	return client.doJSON("DELETE", url, nil, nil)
 }
 
@@ -158,7 +176,7 @@ func (client *Client) DeleteGroup(groupID string) error {
 func (client *Client) GetGroupUsers(groupID string) (*GetGroupUsersResponse, error) {
 
 	var respObj GetGroupUsersResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users", url.PathEscape(groupID))
+	url := client.buildURL("/groups/%s/users", url.PathEscape(groupID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -166,7 +184,7 @@ func (client *Client) GetGroupUsers(groupID string) (*GetGroupUsersResponse, err
 
 //AddGroupUser Grants the specified user permissions to the specified workspace.
 func (client *Client) AddGroupUser(groupID string, request AddGroupUserRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users", url.PathEscape(groupID))
+	url := client.buildURL("/groups/%s/users", url.PathEscape(groupID))
 	err := client.doJSON("POST", url, &request, nil)
 
 	return err
@@ -174,7 +192,7 @@ func (client *Client) AddGroupUser(groupID string, request AddGroupUserRequest)
 
 //UpdateGroupUser Update the specified user permissions to the specified workspace.
 func (client *Client) UpdateGroupUser(groupID string, request UpdateGroupUserRequest) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users", url.PathEscape(groupID))
+	url := client.buildURL("/groups/%s/users", url.PathEscape(groupID))
 	err := client.doJSON("PUT", url, &request, nil)
 
 	return err
@@ -182,8 +200,13 @@ func (client *Client) UpdateGroupUser(groupID string, request UpdateGroupUserReq
 
 //DeleteUserInGroup Deletes the specified user permissions from the specified workspace.
 func (client *Client) DeleteUserInGroup(groupID string, userInfo string) error {
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/users/%s", url.PathEscape(groupID), url.PathEscape(userInfo))
+	url := client.buildURL("/groups/%s/users/%s", url.PathEscape(groupID), url.PathEscape(userInfo))
 	err := client.doJSON("DELETE", url, nil, nil)
 
 	return err
 }
+
+// escapeODataString escapes a value so it can be used in an OData string literal
+func escapeODataString(value string) string {
+	return strings.Replace(value, "'", "''", -1)
+}
@@ -4,9 +4,19 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"os"
 	"time"
 )
 
+// PostImportInGroupRequest represents the options for creating an import in a group
+type PostImportInGroupRequest struct {
+	DatasetDisplayName  string
+	NameConflict        string
+	SkipReport          bool
+	OverrideReportLabel bool
+	OverrideModelLabel  bool
+}
+
 // PostImportInGroupResponse represents the response from creating an inmport in a group
 type PostImportInGroupResponse struct {
 	ID string
@@ -75,27 +85,106 @@ type GetImportsInGroupResponseItemReport struct {
 	WebURL     string
 }
 
-// PostImportInGroup creates an import within the the specified group
-func (client *Client) PostImportInGroup(groupID string, datasetDisplayName string, nameConflict string, skipReport bool, requestData io.Reader) (*PostImportInGroupResponse, error) {
+// PostImportFromURLInGroupRequest represents the request to create an import from a file already uploaded
+type PostImportFromURLInGroupRequest struct {
+	FileURL string `json:"fileUrl"`
+}
 
-	queryParams := url.Values{}
-	if datasetDisplayName != "" {
-		queryParams.Add("datasetDisplayName", datasetDisplayName)
-	}
-	if nameConflict != "" {
-		queryParams.Add("nameConflict", nameConflict)
-	}
-	if skipReport {
-		queryParams.Add("skipReport", "true")
+// CreateTemporaryUploadLocationInGroupResponse represents the response from creating a temporary upload location
+type CreateTemporaryUploadLocationInGroupResponse struct {
+	URL            string
+	ExpirationTime time.Time
+}
+
+// PostImportInGroup creates an import within the the specified group. Files larger than the LargeImportThreshold
+// are uploaded to a temporary upload location and imported from there
+func (client *Client) PostImportInGroup(groupID string, request PostImportInGroupRequest, requestData io.Reader) (*PostImportInGroupResponse, error) {
+
+	if size, sizeKnown := readerSize(requestData); sizeKnown && size > client.LargeImportThreshold {
+		location, err := client.CreateTemporaryUploadLocationInGroup(groupID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = client.UploadToTemporaryLocation(location.URL, requestData)
+		if err != nil {
+			return nil, err
+		}
+
+		return client.PostImportFromURLInGroup(groupID, request, location.URL)
 	}
 
 	var respObj PostImportInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/imports?%s", url.PathEscape(groupID), queryParams.Encode())
+	url := client.buildURL("/groups/%s/imports?%s", url.PathEscape(groupID), importQueryParams(request).Encode())
 	err := client.doMultipartJSON("POST", url, requestData, &respObj)
 
 	return &respObj, err
 }
 
+// PostImportFromURLInGroup creates an import within the the specified group from a file uploaded to a temporary upload location
+func (client *Client) PostImportFromURLInGroup(groupID string, request PostImportInGroupRequest, fileURL string) (*PostImportInGroupResponse, error) {
+
+	var respObj PostImportInGroupResponse
+	url := client.buildURL("/groups/%s/imports?%s", url.PathEscape(groupID), importQueryParams(request).Encode())
+	err := client.doJSON("POST", url, PostImportFromURLInGroupRequest{
+		FileURL: fileURL,
+	}, &respObj)
+
+	return &respObj, err
+}
+
+// CreateTemporaryUploadLocationInGroup creates a temporary blob storage location that large files can be uploaded to before being imported
+func (client *Client) CreateTemporaryUploadLocationInGroup(groupID string) (*CreateTemporaryUploadLocationInGroupResponse, error) {
+
+	var respObj CreateTemporaryUploadLocationInGroupResponse
+	url := client.buildURL("/groups/%s/imports/createTemporaryUploadLocation", url.PathEscape(groupID))
+	err := client.doJSON("POST", url, nil, &respObj)
+
+	return &respObj, err
+}
+
+func importQueryParams(request PostImportInGroupRequest) url.Values {
+	queryParams := url.Values{}
+	if request.DatasetDisplayName != "" {
+		queryParams.Add("datasetDisplayName", request.DatasetDisplayName)
+	}
+	if request.NameConflict != "" {
+		queryParams.Add("nameConflict", request.NameConflict)
+	}
+	if request.SkipReport {
+		queryParams.Add("skipReport", "true")
+	}
+	if request.OverrideReportLabel {
+		queryParams.Add("overrideReportLabel", "true")
+	}
+	if request.OverrideModelLabel {
+		queryParams.Add("overrideModelLabel", "true")
+	}
+	return queryParams
+}
+
+// readerSize returns the number of bytes left to read from files and in memory readers
+func readerSize(reader io.Reader) (int64, bool) {
+	switch reader := reader.(type) {
+	case interface{ Len() int }:
+		return int64(reader.Len()), true
+	case interface {
+		Stat() (os.FileInfo, error)
+		Seek(offset int64, whence int) (int64, error)
+	}:
+		info, err := reader.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return 0, false
+		}
+		offset, err := reader.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, false
+		}
+		return info.Size() - offset, true
+	}
+	return 0, false
+}
+
 // WaitForImportInGroupToSucceed waits until the specified import in group succeeds
 func (client *Client) WaitForImportInGroupToSucceed(groupID string, importID string, timeout time.Duration) (*GetImportInGroupResponse, error) {
 	ticker := time.NewTicker(time.Second)
@@ -125,8 +214,8 @@ func (client *Client) WaitForImportInGroupToSucceed(groupID string, importID str
 func (client *Client) GetImportInGroup(groupID string, importID string) (*GetImportInGroupResponse, error) {
 
 	var respObj GetImportInGroupResponse
-	url := fmt.Sprintf(
-		"https://api.powerbi.com/v1.0/myorg/groups/%s/imports/%s",
+	url := client.buildURL(
+		"/groups/%s/imports/%s",
 		url.PathEscape(groupID),
 		url.PathEscape(importID))
 	err := client.doJSON("GET", url, nil, &respObj)
@@ -138,8 +227,8 @@ func (client *Client) GetImportInGroup(groupID string, importID string) (*GetImp
 func (client *Client) GetImportsInGroup(groupID string) (*GetImportsInGroupResponse, error) {
 
 	var respObj GetImportsInGroupResponse
-	url := fmt.Sprintf(
-		"https://api.powerbi.com/v1.0/myorg/groups/%s/imports",
+	url := client.buildURL(
+		"/groups/%s/imports",
 		url.PathEscape(groupID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
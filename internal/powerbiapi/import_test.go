@@ -0,0 +1,164 @@
+package powerbiapi_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi/powerbiapitest"
+)
+
+func postTestImport(t *testing.T, server *powerbiapitest.Server, client *powerbiapi.Client, content io.Reader) string {
+	group, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: "Imports"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	im, err := client.PostImportInGroup(group.ID, powerbiapi.PostImportInGroupRequest{
+		DatasetDisplayName: "Import",
+		NameConflict:       "CreateOrOverwrite",
+	}, content)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	return im.ID
+}
+
+func TestPostImportInGroup_streamed(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	content := []byte("small pbix content")
+	importID := postTestImport(t, server, client, bytes.NewReader(content))
+
+	if actual := server.ImportContent(importID); !bytes.Equal(actual, content) {
+		t.Fatalf("expected import content %q, found %q", content, actual)
+	}
+	if created := server.TemporaryUploadLocationsCreated(); created != 0 {
+		t.Fatalf("expected no temporary upload locations to be created, found %d", created)
+	}
+}
+
+func TestPostImportInGroup_largeFile(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.LargeImportThreshold = 1024
+
+	// large enough to be uploaded in several blocks
+	content := make([]byte, 9*1024*1024+100)
+	rand.New(rand.NewSource(1)).Read(content)
+	importID := postTestImport(t, server, client, bytes.NewReader(content))
+
+	if actual := server.ImportContent(importID); !bytes.Equal(actual, content) {
+		t.Fatalf("expected import content of %d bytes to match, found %d bytes", len(content), len(actual))
+	}
+	if created := server.TemporaryUploadLocationsCreated(); created != 1 {
+		t.Fatalf("expected 1 temporary upload location to be created, found %d", created)
+	}
+}
+
+func TestPostImportInGroup_unknownSize(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	client.LargeImportThreshold = 1
+
+	// readers without a known size cannot be checked against the threshold so are posted directly
+	content := []byte("pbix content of unknown size")
+	importID := postTestImport(t, server, client, io.MultiReader(bytes.NewReader(content)))
+
+	if actual := server.ImportContent(importID); !bytes.Equal(actual, content) {
+		t.Fatalf("expected import content %q, found %q", content, actual)
+	}
+	if created := server.TemporaryUploadLocationsCreated(); created != 0 {
+		t.Fatalf("expected no temporary upload locations to be created, found %d", created)
+	}
+}
+
+func TestPostImportInGroup_throttled(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	group, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: "Imports"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// the streamed body is read again from the start when the import is retried
+	server.ThrottleRequests(1)
+	content := []byte("throttled pbix content")
+	im, err := client.PostImportInGroup(group.ID, powerbiapi.PostImportInGroupRequest{
+		DatasetDisplayName: "Import",
+		NameConflict:       "CreateOrOverwrite",
+	}, bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual := server.ImportContent(im.ID); !bytes.Equal(actual, content) {
+		t.Fatalf("expected import content %q, found %q", content, actual)
+	}
+}
+
+func TestPostImportInGroup_unauthorized(t *testing.T) {
+	server := powerbiapitest.NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	group, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: "Imports"})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	content := []byte("pbix content uploaded with a revoked token")
+	file, err := ioutil.TempFile("", "*.pbix")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+	file.Write(content)
+	file.Seek(0, io.SeekStart)
+
+	// the file is read again from the start when the import is replayed with a new token
+	server.ExpireTokens()
+	im, err := client.PostImportInGroup(group.ID, powerbiapi.PostImportInGroupRequest{
+		DatasetDisplayName: "Import",
+		NameConflict:       "CreateOrOverwrite",
+	}, file)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	if actual := server.ImportContent(im.ID); !bytes.Equal(actual, content) {
+		t.Fatalf("expected import content %q, found %q", content, actual)
+	}
+	if issued := server.TokensIssued(); issued != 2 {
+		t.Fatalf("expected a new token to be issued for the replay, found %d tokens issued", issued)
+	}
+}
@@ -0,0 +1,91 @@
+package powerbiapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/go-cleanhttp"
+)
+
+const (
+	// files are uploaded to temporary upload locations in blocks of this size
+	uploadBlockSize = 4 * 1024 * 1024
+
+	blobServiceVersion = "2019-12-12"
+)
+
+type blockList struct {
+	XMLName xml.Name `xml:"BlockList"`
+	Latest  []string `xml:"Latest"`
+}
+
+// UploadToTemporaryLocation uploads the content of the reader to a temporary upload location in blocks, so only a
+// single block is held in memory at a time. The upload URL is a shared access signature URL, so is not sent a bearer token
+func (client *Client) UploadToTemporaryLocation(uploadURL string, reader io.Reader) error {
+	// use own http client so we dont add a token to requests to blob storage
+	httpClient := cleanhttp.DefaultClient()
+	httpClient.Transport = newErrorOnUnsuccessfulRoundTripper(httpClient.Transport)
+
+	var blockIDs []string
+	block := make([]byte, uploadBlockSize)
+	for {
+		blockLength, err := io.ReadFull(reader, block)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+
+		// block IDs must all be the same length
+		blockID := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("block-%08d", len(blockIDs))))
+		err = putBlob(httpClient, uploadURL, url.Values{"comp": {"block"}, "blockid": {blockID}}, block[:blockLength])
+		if err != nil {
+			return err
+		}
+		blockIDs = append(blockIDs, blockID)
+
+		if blockLength < uploadBlockSize {
+			break
+		}
+	}
+
+	blockListData, err := xml.Marshal(blockList{Latest: blockIDs})
+	if err != nil {
+		return err
+	}
+	return putBlob(httpClient, uploadURL, url.Values{"comp": {"blocklist"}}, append([]byte(xml.Header), blockListData...))
+}
+
+func putBlob(httpClient *http.Client, uploadURL string, queryParams url.Values, data []byte) error {
+	blobURL, err := url.Parse(uploadURL)
+	if err != nil {
+		return fmt.Errorf("Temporary upload location URL is not valid: %s", err)
+	}
+	query := blobURL.Query()
+	for key, values := range queryParams {
+		query[key] = values
+	}
+	blobURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequest("PUT", blobURL.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-ms-version", blobServiceVersion)
+
+	resp, err := httpClient.Do(req)
+	if urlErr, ok := err.(*url.Error); ok {
+		// the URL holds the shared access signature so is left out of errors
+		return fmt.Errorf("Unable to upload to temporary upload location: %s", urlErr.Err)
+	}
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
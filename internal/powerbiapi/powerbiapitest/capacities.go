@@ -0,0 +1,76 @@
+package powerbiapitest
+
+import (
+	"net/http"
+	"strings"
+)
+
+const unassignedCapacityID = "00000000-0000-0000-0000-000000000000"
+
+// Capacity represents a capacity that workspaces can be assigned to
+type Capacity struct {
+	ID                      string   `json:"id"`
+	DisplayName             string   `json:"displayName"`
+	Admins                  []string `json:"admins"`
+	SKU                     string   `json:"sku"`
+	State                   string   `json:"state"`
+	Region                  string   `json:"region"`
+	CapacityUserAccessRight string   `json:"capacityUserAccessRight"`
+}
+
+// AddCapacity adds a capacity to the service, generating an ID if one is not provided
+func (server *Server) AddCapacity(capacity Capacity) Capacity {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	if capacity.ID == "" {
+		capacity.ID = newID()
+	}
+	if capacity.State == "" {
+		capacity.State = "Active"
+	}
+	if capacity.CapacityUserAccessRight == "" {
+		capacity.CapacityUserAccessRight = "Admin"
+	}
+	server.capacities = append(server.capacities, &capacity)
+	return capacity
+}
+
+func (server *Server) findCapacity(capacityID string) *Capacity {
+	for _, c := range server.capacities {
+		if strings.EqualFold(c.ID, capacityID) {
+			return c
+		}
+	}
+	return nil
+}
+
+func (server *Server) getCapacities(w http.ResponseWriter, r *http.Request, params []string) {
+	writeJSON(w, valueResponse{Value: server.capacities})
+}
+
+func (server *Server) groupAssignToCapacity(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	var request struct {
+		CapacityID string `json:"capacityId"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.CapacityID == unassignedCapacityID {
+		g.CapacityID = ""
+		return
+	}
+
+	capacity := server.findCapacity(request.CapacityID)
+	if capacity == nil {
+		writeNotFound(w, "capacity", request.CapacityID)
+		return
+	}
+	g.CapacityID = capacity.ID
+}
@@ -0,0 +1,76 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type dataflow struct {
+	ID          string
+	GroupID     string
+	Name        string
+	Description string
+}
+
+type dataflowJSON struct {
+	ObjectID     string `json:"objectId"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	ModelURL     string `json:"modelUrl"`
+	ConfiguredBy string `json:"configuredBy"`
+}
+
+func (df *dataflow) toJSON() dataflowJSON {
+	return dataflowJSON{
+		ObjectID:     df.ID,
+		Name:         df.Name,
+		Description:  df.Description,
+		ModelURL:     fmt.Sprintf("https://fake.dfs.core.windows.net/powerbi/%s/%s/model.json", df.GroupID, df.ID),
+		ConfiguredBy: "test@example.com",
+	}
+}
+
+func (server *Server) findDataflowByName(groupID string, name string) *dataflow {
+	for _, df := range server.dataflows {
+		if strings.EqualFold(df.GroupID, groupID) && strings.EqualFold(df.Name, name) {
+			return df
+		}
+	}
+	return nil
+}
+
+func (server *Server) getDataflowsInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findGroupOrNotFound(w, params[0]) == nil {
+		return
+	}
+
+	items := make([]dataflowJSON, 0)
+	for _, df := range server.dataflows {
+		if strings.EqualFold(df.GroupID, params[0]) {
+			items = append(items, df.toJSON())
+		}
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) deleteDataflowInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findGroupOrNotFound(w, params[0]) == nil {
+		return
+	}
+
+	dataflows := server.dataflows[:0]
+	found := false
+	for _, existing := range server.dataflows {
+		if strings.EqualFold(existing.GroupID, params[0]) && strings.EqualFold(existing.ID, params[1]) {
+			found = true
+			continue
+		}
+		dataflows = append(dataflows, existing)
+	}
+	server.dataflows = dataflows
+
+	if !found {
+		writeNotFound(w, "dataflow", params[1])
+	}
+}
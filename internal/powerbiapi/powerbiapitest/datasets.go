@@ -0,0 +1,429 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type dataset struct {
+	ID                  string
+	GroupID             string
+	Name                string
+	ConfiguredBy        string
+	AddRowsAPIEnabled   bool
+	IsRefreshable       bool
+	TargetStorageMode   string
+	Tables              []*table
+	Parameters          []*Parameter
+	Datasources         []*Datasource
+	RefreshSchedule     refreshSchedule
+	DirectQuerySchedule directQueryRefreshSchedule
+	Refreshes           []*refresh
+}
+
+type table struct {
+	Name     string        `json:"name"`
+	Columns  []interface{} `json:"columns,omitempty"`
+	Measures []interface{} `json:"measures,omitempty"`
+	RowCount int           `json:"-"`
+}
+
+type refreshSchedule struct {
+	Enabled         bool     `json:"enabled"`
+	Days            []string `json:"days"`
+	Times           []string `json:"times"`
+	LocalTimeZoneID string   `json:"localTimeZoneId"`
+	NotifyOption    string   `json:"notifyOption"`
+}
+
+type directQueryRefreshSchedule struct {
+	Frequency       int      `json:"frequency,omitempty"`
+	Days            []string `json:"days"`
+	Times           []string `json:"times"`
+	LocalTimeZoneID string   `json:"localTimeZoneId"`
+}
+
+type datasetJSON struct {
+	ID                               string `json:"id"`
+	Name                             string `json:"name"`
+	WebURL                           string `json:"webUrl"`
+	AddRowsAPIEnabled                bool   `json:"addRowsAPIEnabled"`
+	ConfiguredBy                     string `json:"configuredBy"`
+	IsRefreshable                    bool   `json:"isRefreshable"`
+	IsEffectiveIdentityRequired      bool   `json:"isEffectiveIdentityRequired"`
+	IsEffectiveIdentityRolesRequired bool   `json:"isEffectiveIdentityRolesRequired"`
+	IsOnPremGatewayRequired          bool   `json:"isOnPremGatewayRequired"`
+	TargetStorageMode                string `json:"targetStorageMode"`
+}
+
+func newDataset(groupID string, name string) *dataset {
+	return &dataset{
+		ID:                newID(),
+		GroupID:           groupID,
+		Name:              name,
+		ConfiguredBy:      testClientID,
+		IsRefreshable:     true,
+		TargetStorageMode: "Abf",
+		RefreshSchedule: refreshSchedule{
+			Days:            []string{},
+			Times:           []string{},
+			LocalTimeZoneID: "UTC",
+			NotifyOption:    "MailOnFailure",
+		},
+		DirectQuerySchedule: directQueryRefreshSchedule{
+			Frequency:       60,
+			Days:            []string{},
+			Times:           []string{},
+			LocalTimeZoneID: "UTC",
+		},
+	}
+}
+
+func (d *dataset) toJSON() datasetJSON {
+	return datasetJSON{
+		ID:                d.ID,
+		Name:              d.Name,
+		WebURL:            fmt.Sprintf("https://app.powerbi.com/groups/%s/datasets/%s", d.GroupID, d.ID),
+		AddRowsAPIEnabled: d.AddRowsAPIEnabled,
+		ConfiguredBy:      d.ConfiguredBy,
+		IsRefreshable:     d.IsRefreshable,
+		TargetStorageMode: d.TargetStorageMode,
+	}
+}
+
+// resetContent replaces parameters and datasources with the defaults, as happens when a PBIX is uploaded
+func (server *Server) resetContent(d *dataset) {
+	d.Parameters = make([]*Parameter, 0, len(server.DefaultParameters))
+	for _, parameter := range server.DefaultParameters {
+		parameter := parameter
+		d.Parameters = append(d.Parameters, &parameter)
+	}
+
+	d.Datasources = make([]*Datasource, 0, len(server.DefaultDatasources))
+	for _, datasource := range server.DefaultDatasources {
+		datasource := datasource
+		connectionDetails := make(map[string]string)
+		for key, value := range datasource.ConnectionDetails {
+			connectionDetails[key] = value
+		}
+		datasource.ConnectionDetails = connectionDetails
+		if datasource.DatasourceID == "" {
+			datasource.DatasourceID = newID()
+		}
+		// datasources that are not on an on-premises gateway use a cloud gateway of their own
+		if datasource.GatewayID == "" {
+			datasource.GatewayID = newID()
+		}
+		d.Datasources = append(d.Datasources, &datasource)
+	}
+}
+
+func (server *Server) findDataset(groupID string, datasetID string) *dataset {
+	for _, d := range server.datasets {
+		if strings.EqualFold(d.ID, datasetID) && (groupID == "" || strings.EqualFold(d.GroupID, groupID)) {
+			return d
+		}
+	}
+	return nil
+}
+
+// findDatasetOrNotFound finds a dataset, writing a not found response if it does not exist
+func (server *Server) findDatasetOrNotFound(w http.ResponseWriter, groupID string, datasetID string) *dataset {
+	if server.findGroupOrNotFound(w, groupID) == nil {
+		return nil
+	}
+	d := server.findDataset(groupID, datasetID)
+	if d == nil {
+		writeNotFound(w, "dataset", datasetID)
+	}
+	return d
+}
+
+func (server *Server) getDatasetsInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findGroupOrNotFound(w, params[0]) == nil {
+		return
+	}
+
+	items := make([]datasetJSON, 0)
+	for _, d := range server.datasets {
+		if strings.EqualFold(d.GroupID, params[0]) {
+			items = append(items, d.toJSON())
+		}
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) getDatasetInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	writeJSON(w, d.toJSON())
+}
+
+func (server *Server) deleteDatasetInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	datasets := server.datasets[:0]
+	for _, existing := range server.datasets {
+		if existing != d {
+			datasets = append(datasets, existing)
+		}
+	}
+	server.datasets = datasets
+}
+
+func (server *Server) getParametersInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	writeJSON(w, valueResponse{Value: d.Parameters})
+}
+
+func (server *Server) updateParametersInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request struct {
+		UpdateDetails []struct {
+			Name     string
+			NewValue string
+		}
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	// validate everything before applying so a failed request makes no changes
+	for _, updateDetail := range request.UpdateDetails {
+		if findParameter(d, updateDetail.Name) == nil {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Dataset does not contain parameter '%s'", updateDetail.Name))
+			return
+		}
+	}
+	for _, updateDetail := range request.UpdateDetails {
+		findParameter(d, updateDetail.Name).CurrentValue = updateDetail.NewValue
+	}
+}
+
+func (server *Server) getDatasourcesInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	writeJSON(w, valueResponse{Value: d.Datasources})
+}
+
+func (server *Server) updateDatasourcesInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request struct {
+		UpdateDetails []struct {
+			DatasourceSelector struct {
+				DatasourceType    string
+				ConnectionDetails map[string]*string
+				DatasourceID      string
+				GatewayID         string
+			}
+			ConnectionDetails map[string]*string
+		}
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	// validate everything before applying so a failed request makes no changes
+	matches := make([][]*Datasource, len(request.UpdateDetails))
+	for i, updateDetail := range request.UpdateDetails {
+		selector := normalizeConnectionDetails(updateDetail.DatasourceSelector.ConnectionDetails)
+		for _, datasource := range d.Datasources {
+			if updateDetail.DatasourceSelector.DatasourceID != "" && !strings.EqualFold(datasource.DatasourceID, updateDetail.DatasourceSelector.DatasourceID) {
+				continue
+			}
+			if updateDetail.DatasourceSelector.GatewayID != "" && !strings.EqualFold(datasource.GatewayID, updateDetail.DatasourceSelector.GatewayID) {
+				continue
+			}
+			if datasourceMatches(datasource, updateDetail.DatasourceSelector.DatasourceType, selector) {
+				matches[i] = append(matches[i], datasource)
+			}
+		}
+		if len(matches[i]) == 0 {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("No datasource matches the selector in update %d", i))
+			return
+		}
+	}
+	for i, updateDetail := range request.UpdateDetails {
+		for _, datasource := range matches[i] {
+			for key, value := range normalizeConnectionDetails(updateDetail.ConnectionDetails) {
+				datasource.ConnectionDetails[key] = value
+			}
+		}
+	}
+}
+
+func (server *Server) getRefreshScheduleInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	writeJSON(w, d.RefreshSchedule)
+}
+
+func (server *Server) updateRefreshScheduleInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request struct {
+		Value struct {
+			Enabled         *bool     `json:"enabled"`
+			Days            *[]string `json:"days"`
+			Times           *[]string `json:"times"`
+			LocalTimeZoneID *string   `json:"localTimeZoneId"`
+			NotifyOption    *string   `json:"notifyOption"`
+		} `json:"value"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	value := request.Value
+	if value.Enabled != nil {
+		d.RefreshSchedule.Enabled = *value.Enabled
+	}
+	if value.Days != nil {
+		d.RefreshSchedule.Days = *value.Days
+	}
+	if value.Times != nil {
+		d.RefreshSchedule.Times = *value.Times
+	}
+	if value.LocalTimeZoneID != nil {
+		d.RefreshSchedule.LocalTimeZoneID = *value.LocalTimeZoneID
+	}
+	if value.NotifyOption != nil {
+		d.RefreshSchedule.NotifyOption = *value.NotifyOption
+	}
+}
+
+func (server *Server) getDirectQueryRefreshScheduleInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	writeJSON(w, d.DirectQuerySchedule)
+}
+
+func (server *Server) updateDirectQueryRefreshScheduleInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request struct {
+		Value struct {
+			Frequency       *int      `json:"frequency"`
+			Days            *[]string `json:"days"`
+			Times           *[]string `json:"times"`
+			LocalTimeZoneID *string   `json:"localTimeZoneId"`
+		} `json:"value"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	// a schedule either refreshes at a frequency, or on days and times
+	value := request.Value
+	if value.Frequency != nil && (value.Days != nil || value.Times != nil) {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "frequency cannot be combined with days and times")
+		return
+	}
+	if value.Frequency != nil {
+		switch *value.Frequency {
+		case 15, 30, 60, 120, 180:
+		default:
+			writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Unsupported frequency %d", *value.Frequency))
+			return
+		}
+		d.DirectQuerySchedule.Frequency = *value.Frequency
+		d.DirectQuerySchedule.Days = []string{}
+		d.DirectQuerySchedule.Times = []string{}
+	}
+	if value.Days != nil {
+		d.DirectQuerySchedule.Frequency = 0
+		d.DirectQuerySchedule.Days = *value.Days
+	}
+	if value.Times != nil {
+		d.DirectQuerySchedule.Frequency = 0
+		d.DirectQuerySchedule.Times = *value.Times
+	}
+	if value.LocalTimeZoneID != nil {
+		d.DirectQuerySchedule.LocalTimeZoneID = *value.LocalTimeZoneID
+	}
+}
+
+func findParameter(d *dataset, name string) *Parameter {
+	for _, parameter := range d.Parameters {
+		if parameter.Name == name {
+			return parameter
+		}
+	}
+	return nil
+}
+
+func datasourceMatches(datasource *Datasource, datasourceType string, selector map[string]string) bool {
+	if datasourceType != "" && !strings.EqualFold(datasource.DatasourceType, datasourceType) {
+		return false
+	}
+	for key, value := range selector {
+		if !strings.EqualFold(datasource.ConnectionDetails[key], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeConnectionDetails removes null values and converts keys to the camel case used in responses
+func normalizeConnectionDetails(connectionDetails map[string]*string) map[string]string {
+	normalized := make(map[string]string)
+	for key, value := range connectionDetails {
+		if value == nil {
+			continue
+		}
+		if key == strings.ToUpper(key) {
+			normalized[strings.ToLower(key)] = *value
+			continue
+		}
+		first, size := utf8.DecodeRuneInString(key)
+		normalized[string(unicode.ToLower(first))+key[size:]] = *value
+	}
+	return normalized
+}
+
+func (server *Server) updateSemanticModel(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request fabricItemUpdate
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.DisplayName != nil {
+		d.Name = *request.DisplayName
+	}
+	writeJSON(w, fabricItemJSON{ID: d.ID, Type: "SemanticModel", DisplayName: d.Name, WorkspaceID: d.GroupID})
+}
@@ -0,0 +1,652 @@
+package powerbiapitest
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+// Gateway represents an on-premises data gateway
+type Gateway struct {
+	ID          string
+	Name        string
+	Type        string
+	Datasources []GatewayDatasource
+
+	// PrivateKey decrypts credentials sent to the gateway. A key shared by all gateways is generated if one is not provided
+	PrivateKey *rsa.PrivateKey
+}
+
+// GatewayDatasource represents a datasource configured on a gateway
+type GatewayDatasource struct {
+	ID                string
+	DatasourceType    string
+	DatasourceName    string
+	ConnectionDetails map[string]string
+	CredentialType    string
+	PrivacyLevel      string
+
+	// Credentials are the decrypted credentials last given to the datasource
+	Credentials string
+
+	users []*gatewayDatasourceUser
+}
+
+type gatewayDatasourceUser struct {
+	DatasourceAccessRight string `json:"datasourceAccessRight"`
+	DisplayName           string `json:"displayName,omitempty"`
+	EmailAddress          string `json:"emailAddress,omitempty"`
+	Identifier            string `json:"identifier"`
+	PrincipalType         string `json:"principalType,omitempty"`
+}
+
+type gatewayDatasourceJSON struct {
+	ID                string `json:"id"`
+	GatewayID         string `json:"gatewayId"`
+	DatasourceType    string `json:"datasourceType"`
+	DatasourceName    string `json:"datasourceName"`
+	ConnectionDetails string `json:"connectionDetails"`
+	CredentialType    string `json:"credentialType"`
+}
+
+type gatewayCredentialDetailsJSON struct {
+	CredentialType      string `json:"credentialType"`
+	Credentials         string `json:"credentials"`
+	EncryptedConnection string `json:"encryptedConnection"`
+	EncryptionAlgorithm string `json:"encryptionAlgorithm"`
+	PrivacyLevel        string `json:"privacyLevel"`
+}
+
+type gatewayJSON struct {
+	ID            string               `json:"id"`
+	Name          string               `json:"name"`
+	Type          string               `json:"type"`
+	PublicKey     gatewayPublicKeyJSON `json:"publicKey"`
+	GatewayStatus string               `json:"gatewayStatus"`
+}
+
+type gatewayPublicKeyJSON struct {
+	Exponent string `json:"exponent"`
+	Modulus  string `json:"modulus"`
+}
+
+// AddGateway adds a gateway to the service, generating IDs for the gateway and its datasources if they are not provided
+func (server *Server) AddGateway(gateway Gateway) Gateway {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	if gateway.ID == "" {
+		gateway.ID = newID()
+	}
+	if gateway.Type == "" {
+		gateway.Type = "Resource"
+	}
+	if gateway.PrivateKey == nil {
+		if server.gatewayKey == nil {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				panic(err)
+			}
+			server.gatewayKey = key
+		}
+		gateway.PrivateKey = server.gatewayKey
+	}
+	datasources := make([]GatewayDatasource, 0, len(gateway.Datasources))
+	for _, datasource := range gateway.Datasources {
+		if datasource.ID == "" {
+			datasource.ID = newID()
+		}
+		if datasource.CredentialType == "" {
+			datasource.CredentialType = "Anonymous"
+		}
+		datasources = append(datasources, datasource)
+	}
+	gateway.Datasources = datasources
+
+	server.gateways = append(server.gateways, &gateway)
+	return gateway
+}
+
+// PublicKey returns the base64 encoded exponent and modulus of the public key used to encrypt credentials for the gateway
+func (gateway *Gateway) PublicKey() (string, string) {
+	exponent := base64.StdEncoding.EncodeToString(big.NewInt(int64(gateway.PrivateKey.E)).Bytes())
+	modulus := base64.StdEncoding.EncodeToString(gateway.PrivateKey.N.Bytes())
+	return exponent, modulus
+}
+
+// DatasourceCredentials returns the decrypted credentials last given to a gateway or cloud datasource
+func (server *Server) DatasourceCredentials(gatewayID string, datasourceID string) string {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	if gateway := server.findGateway(gatewayID); gateway != nil {
+		for _, datasource := range gateway.Datasources {
+			if strings.EqualFold(datasource.ID, datasourceID) {
+				return datasource.Credentials
+			}
+		}
+	}
+	if datasource := server.findCloudDatasource(gatewayID, datasourceID); datasource != nil {
+		return datasource.Credentials
+	}
+	return ""
+}
+
+// SetDatasetOwner changes the owner of a dataset, as if another user had taken it over
+func (server *Server) SetDatasetOwner(datasetID string, configuredBy string) {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	if d := server.findDataset("", datasetID); d != nil {
+		d.ConfiguredBy = configuredBy
+	}
+}
+
+func (gateway *Gateway) toJSON() gatewayJSON {
+	exponent, modulus := gateway.PublicKey()
+	return gatewayJSON{
+		ID:   gateway.ID,
+		Name: gateway.Name,
+		Type: gateway.Type,
+		PublicKey: gatewayPublicKeyJSON{
+			Exponent: exponent,
+			Modulus:  modulus,
+		},
+		GatewayStatus: "Live",
+	}
+}
+
+func (server *Server) findGateway(gatewayID string) *Gateway {
+	for _, gateway := range server.gateways {
+		if strings.EqualFold(gateway.ID, gatewayID) {
+			return gateway
+		}
+	}
+	return nil
+}
+
+func (datasource *GatewayDatasource) toJSON(gatewayID string) gatewayDatasourceJSON {
+	connectionDetails, _ := json.Marshal(datasource.ConnectionDetails)
+	return gatewayDatasourceJSON{
+		ID:                datasource.ID,
+		GatewayID:         gatewayID,
+		DatasourceType:    datasource.DatasourceType,
+		DatasourceName:    datasource.DatasourceName,
+		ConnectionDetails: string(connectionDetails),
+		CredentialType:    datasource.CredentialType,
+	}
+}
+
+func (server *Server) findGatewayOrNotFound(w http.ResponseWriter, gatewayID string) *Gateway {
+	gateway := server.findGateway(gatewayID)
+	if gateway == nil {
+		writeNotFound(w, "gateway", gatewayID)
+	}
+	return gateway
+}
+
+func (server *Server) findGatewayDatasourceOrNotFound(w http.ResponseWriter, gatewayID string, datasourceID string) (*Gateway, *GatewayDatasource) {
+	gateway := server.findGatewayOrNotFound(w, gatewayID)
+	if gateway == nil {
+		return nil, nil
+	}
+	for i := range gateway.Datasources {
+		if strings.EqualFold(gateway.Datasources[i].ID, datasourceID) {
+			return gateway, &gateway.Datasources[i]
+		}
+	}
+	writeNotFound(w, "gateway datasource", datasourceID)
+	return nil, nil
+}
+
+func findGatewayDatasourceUser(datasource *GatewayDatasource, identifier string) *gatewayDatasourceUser {
+	for _, user := range datasource.users {
+		if strings.EqualFold(user.Identifier, identifier) || (user.EmailAddress != "" && strings.EqualFold(user.EmailAddress, identifier)) {
+			return user
+		}
+	}
+	return nil
+}
+
+// findCloudDatasource finds a dataset datasource that is not bound to an on-premises gateway
+func (server *Server) findCloudDatasource(gatewayID string, datasourceID string) *Datasource {
+	for _, d := range server.datasets {
+		for _, datasource := range d.Datasources {
+			if strings.EqualFold(datasource.GatewayID, gatewayID) && strings.EqualFold(datasource.DatasourceID, datasourceID) {
+				return datasource
+			}
+		}
+	}
+	return nil
+}
+
+// readCredentialDetails checks credentials in the same way as the service, returning the decrypted
+// credentials or an error message if they are not valid. Cloud datasources have no gateway
+func readCredentialDetails(gateway *Gateway, credentialDetails gatewayCredentialDetailsJSON) (string, string) {
+	credentials := credentialDetails.Credentials
+	switch credentialDetails.EncryptionAlgorithm {
+	case "None":
+		// on-premises gateways only accept credentials encrypted with their public key
+		if gateway != nil && credentialDetails.CredentialType != "Anonymous" {
+			return "", "Credentials for an on-premises gateway must be encrypted"
+		}
+	case "RSA-OAEP":
+		if gateway == nil {
+			return "", "Credentials for a cloud datasource must not be encrypted"
+		}
+		decrypted, err := decryptCredentials(gateway.PrivateKey, credentials)
+		if err != nil {
+			return "", fmt.Sprintf("Could not decrypt credentials: %s", err)
+		}
+		credentials = decrypted
+	default:
+		return "", fmt.Sprintf("Unknown encryption algorithm '%s'", credentialDetails.EncryptionAlgorithm)
+	}
+
+	var credentialData struct {
+		CredentialData json.RawMessage `json:"credentialData"`
+	}
+	if err := json.Unmarshal([]byte(credentials), &credentialData); err != nil {
+		return "", fmt.Sprintf("Credentials are not valid JSON: %s", err)
+	}
+
+	var items []struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	json.Unmarshal(credentialData.CredentialData, &items)
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Name)
+	}
+
+	var expectedNames []string
+	switch credentialDetails.CredentialType {
+	case "Anonymous":
+	case "Basic", "Windows":
+		expectedNames = []string{"username", "password"}
+	case "Key":
+		expectedNames = []string{"key"}
+	case "OAuth2":
+		expectedNames = []string{"accessToken"}
+	default:
+		return "", fmt.Sprintf("Unknown credential type '%s'", credentialDetails.CredentialType)
+	}
+	if strings.Join(names, ",") != strings.Join(expectedNames, ",") {
+		return "", fmt.Sprintf("Credentials of type '%s' should contain %v. Found %v", credentialDetails.CredentialType, expectedNames, names)
+	}
+
+	return credentials, ""
+}
+
+// decryptCredentials reverses the encryption clients perform with the gateway public key
+func decryptCredentials(key *rsa.PrivateKey, encrypted string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return "", err
+	}
+
+	// smaller keys encrypt the credentials directly in segments
+	if key.Size() < 256 {
+		var decrypted []byte
+		for start := 0; start < len(data); start += key.Size() {
+			if start+key.Size() > len(data) {
+				return "", fmt.Errorf("incomplete segment")
+			}
+			segment, err := rsa.DecryptOAEP(sha1.New(), nil, key, data[start:start+key.Size()], nil)
+			if err != nil {
+				return "", err
+			}
+			decrypted = append(decrypted, segment...)
+		}
+		return string(decrypted), nil
+	}
+
+	// larger keys encrypt ephemeral AES and HMAC keys, followed by algorithm choices, tag, IV and ciphertext
+	if len(data) < key.Size()+2+sha256.Size+aes.BlockSize {
+		return "", fmt.Errorf("encrypted credentials are too short")
+	}
+	keys, err := rsa.DecryptOAEP(sha1.New(), nil, key, data[:key.Size()], nil)
+	if err != nil {
+		return "", err
+	}
+	if len(keys) != 96 {
+		return "", fmt.Errorf("expected 96 bytes of keys, found %d", len(keys))
+	}
+
+	authenticated := data[key.Size():]
+	algorithmChoices := authenticated[:2]
+	tag := authenticated[2 : 2+sha256.Size]
+	iv := authenticated[2+sha256.Size : 2+sha256.Size+aes.BlockSize]
+	ciphertext := authenticated[2+sha256.Size+aes.BlockSize:]
+	if !bytes.Equal(algorithmChoices, []byte{0, 0}) {
+		return "", fmt.Errorf("unsupported algorithm choices %v", algorithmChoices)
+	}
+
+	mac := hmac.New(sha256.New, keys[32:])
+	mac.Write(algorithmChoices)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	if !hmac.Equal(tag, mac.Sum(nil)) {
+		return "", fmt.Errorf("tag does not match")
+	}
+
+	block, err := aes.NewCipher(keys[:32])
+	if err != nil {
+		return "", err
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return "", fmt.Errorf("ciphertext is not a whole number of blocks")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return "", fmt.Errorf("invalid padding")
+	}
+	return string(plaintext[:len(plaintext)-padding]), nil
+}
+
+func (server *Server) getGateways(w http.ResponseWriter, r *http.Request, params []string) {
+	items := make([]gatewayJSON, 0, len(server.gateways))
+	for _, gateway := range server.gateways {
+		items = append(items, gateway.toJSON())
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) getGateway(w http.ResponseWriter, r *http.Request, params []string) {
+	gateway := server.findGatewayOrNotFound(w, params[0])
+	if gateway == nil {
+		return
+	}
+	writeJSON(w, gateway.toJSON())
+}
+
+func (server *Server) getGatewayDatasources(w http.ResponseWriter, r *http.Request, params []string) {
+	gateway := server.findGatewayOrNotFound(w, params[0])
+	if gateway == nil {
+		return
+	}
+
+	items := make([]gatewayDatasourceJSON, 0, len(gateway.Datasources))
+	for i := range gateway.Datasources {
+		items = append(items, gateway.Datasources[i].toJSON(gateway.ID))
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) getGatewayDatasource(w http.ResponseWriter, r *http.Request, params []string) {
+	gateway, datasource := server.findGatewayDatasourceOrNotFound(w, params[0], params[1])
+	if datasource == nil {
+		return
+	}
+	writeJSON(w, datasource.toJSON(gateway.ID))
+}
+
+func (server *Server) createGatewayDatasource(w http.ResponseWriter, r *http.Request, params []string) {
+	gateway := server.findGatewayOrNotFound(w, params[0])
+	if gateway == nil {
+		return
+	}
+
+	var request struct {
+		DataSourceType    string                       `json:"dataSourceType"`
+		ConnectionDetails string                       `json:"connectionDetails"`
+		DatasourceName    string                       `json:"datasourceName"`
+		CredentialDetails gatewayCredentialDetailsJSON `json:"credentialDetails"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	var connectionDetails map[string]string
+	if err := json.Unmarshal([]byte(request.ConnectionDetails), &connectionDetails); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("connectionDetails is not valid JSON: %s", err))
+		return
+	}
+	credentials, message := readCredentialDetails(gateway, request.CredentialDetails)
+	if message != "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", message)
+		return
+	}
+	for _, existing := range gateway.Datasources {
+		if strings.EqualFold(existing.DatasourceName, request.DatasourceName) {
+			writeError(w, http.StatusConflict, "DMTS_DatasourceNameAlreadyExistsError", fmt.Sprintf("Datasource '%s' already exists on gateway '%s'", request.DatasourceName, gateway.ID))
+			return
+		}
+	}
+
+	datasource := GatewayDatasource{
+		ID:                newID(),
+		DatasourceType:    request.DataSourceType,
+		DatasourceName:    request.DatasourceName,
+		ConnectionDetails: connectionDetails,
+		CredentialType:    request.CredentialDetails.CredentialType,
+		PrivacyLevel:      request.CredentialDetails.PrivacyLevel,
+		Credentials:       credentials,
+	}
+	gateway.Datasources = append(gateway.Datasources, datasource)
+	writeJSON(w, datasource.toJSON(gateway.ID))
+}
+
+func (server *Server) updateGatewayDatasource(w http.ResponseWriter, r *http.Request, params []string) {
+	var request struct {
+		CredentialDetails gatewayCredentialDetailsJSON `json:"credentialDetails"`
+	}
+
+	// datasources of datasets that are not bound to an on-premises gateway are on their own cloud gateway
+	if cloudDatasource := server.findCloudDatasource(params[0], params[1]); cloudDatasource != nil && server.findGateway(params[0]) == nil {
+		if !readJSON(w, r, &request) {
+			return
+		}
+		credentials, message := readCredentialDetails(nil, request.CredentialDetails)
+		if message != "" {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", message)
+			return
+		}
+		cloudDatasource.CredentialType = request.CredentialDetails.CredentialType
+		cloudDatasource.Credentials = credentials
+		return
+	}
+
+	gateway, datasource := server.findGatewayDatasourceOrNotFound(w, params[0], params[1])
+	if datasource == nil {
+		return
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+	credentials, message := readCredentialDetails(gateway, request.CredentialDetails)
+	if message != "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", message)
+		return
+	}
+
+	datasource.CredentialType = request.CredentialDetails.CredentialType
+	datasource.PrivacyLevel = request.CredentialDetails.PrivacyLevel
+	datasource.Credentials = credentials
+}
+
+func (server *Server) deleteGatewayDatasource(w http.ResponseWriter, r *http.Request, params []string) {
+	gateway, datasource := server.findGatewayDatasourceOrNotFound(w, params[0], params[1])
+	if datasource == nil {
+		return
+	}
+
+	datasources := make([]GatewayDatasource, 0, len(gateway.Datasources))
+	for _, existing := range gateway.Datasources {
+		if existing.ID != datasource.ID {
+			datasources = append(datasources, existing)
+		}
+	}
+	gateway.Datasources = datasources
+}
+
+func (server *Server) getGatewayDatasourceUsers(w http.ResponseWriter, r *http.Request, params []string) {
+	_, datasource := server.findGatewayDatasourceOrNotFound(w, params[0], params[1])
+	if datasource == nil {
+		return
+	}
+
+	users := make([]*gatewayDatasourceUser, 0, len(datasource.users))
+	users = append(users, datasource.users...)
+	writeJSON(w, valueResponse{Value: users})
+}
+
+func (server *Server) addGatewayDatasourceUser(w http.ResponseWriter, r *http.Request, params []string) {
+	_, datasource := server.findGatewayDatasourceOrNotFound(w, params[0], params[1])
+	if datasource == nil {
+		return
+	}
+
+	var request gatewayDatasourceUser
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Identifier == "" {
+		request.Identifier = request.EmailAddress
+	}
+	if request.Identifier == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "Either identifier or emailAddress is required")
+		return
+	}
+
+	// adding an existing user updates their access
+	if existing := findGatewayDatasourceUser(datasource, request.Identifier); existing != nil {
+		existing.DatasourceAccessRight = request.DatasourceAccessRight
+		return
+	}
+	datasource.users = append(datasource.users, &request)
+}
+
+func (server *Server) deleteGatewayDatasourceUser(w http.ResponseWriter, r *http.Request, params []string) {
+	_, datasource := server.findGatewayDatasourceOrNotFound(w, params[0], params[1])
+	if datasource == nil {
+		return
+	}
+
+	user := findGatewayDatasourceUser(datasource, params[2])
+	if user == nil {
+		writeNotFound(w, "user", params[2])
+		return
+	}
+
+	users := datasource.users[:0]
+	for _, existing := range datasource.users {
+		if existing != user {
+			users = append(users, existing)
+		}
+	}
+	datasource.users = users
+}
+
+func (server *Server) takeOverInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	d.ConfiguredBy = testClientID
+}
+
+func (server *Server) discoverGatewaysInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findDatasetOrNotFound(w, params[0], params[1]) == nil {
+		return
+	}
+
+	items := make([]gatewayJSON, 0, len(server.gateways))
+	for _, gateway := range server.gateways {
+		items = append(items, gateway.toJSON())
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) bindToGatewayInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request struct {
+		GatewayObjectID     string   `json:"gatewayObjectId"`
+		DatasourceObjectIDs []string `json:"datasourceObjectIds"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if d.ConfiguredBy != testClientID {
+		writeError(w, http.StatusForbidden, "PowerBINotAuthorizedException", "Only the owner of the dataset can bind it to a gateway")
+		return
+	}
+	gateway := server.findGateway(request.GatewayObjectID)
+	if gateway == nil {
+		writeNotFound(w, "gateway", request.GatewayObjectID)
+		return
+	}
+
+	// without datasource IDs every datasource on the gateway is a candidate
+	candidates := gateway.Datasources
+	if len(request.DatasourceObjectIDs) > 0 {
+		candidates = nil
+		for _, datasourceID := range request.DatasourceObjectIDs {
+			var found *GatewayDatasource
+			for i := range gateway.Datasources {
+				if strings.EqualFold(gateway.Datasources[i].ID, datasourceID) {
+					found = &gateway.Datasources[i]
+				}
+			}
+			if found == nil {
+				writeNotFound(w, "gateway datasource", datasourceID)
+				return
+			}
+			candidates = append(candidates, *found)
+		}
+	}
+
+	// validate everything before applying so a failed request makes no changes
+	matches := make([]*GatewayDatasource, len(d.Datasources))
+	for i, datasource := range d.Datasources {
+		for j := range candidates {
+			if gatewayDatasourceMatches(&candidates[j], datasource) {
+				matches[i] = &candidates[j]
+				break
+			}
+		}
+		if matches[i] == nil && !datasource.Cloud {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("No datasource on gateway '%s' matches datasource '%s'", gateway.ID, datasource.DatasourceID))
+			return
+		}
+	}
+	for i, datasource := range d.Datasources {
+		if matches[i] == nil {
+			continue
+		}
+		datasource.GatewayID = gateway.ID
+		datasource.DatasourceID = matches[i].ID
+	}
+}
+
+func gatewayDatasourceMatches(gatewayDatasource *GatewayDatasource, datasource *Datasource) bool {
+	if !strings.EqualFold(gatewayDatasource.DatasourceType, datasource.DatasourceType) {
+		return false
+	}
+	for key, value := range datasource.ConnectionDetails {
+		if !strings.EqualFold(gatewayDatasource.ConnectionDetails[key], value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,323 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type group struct {
+	ID                          string
+	Name                        string
+	CapacityID                  string
+	Description                 string
+	DefaultDatasetStorageFormat string
+	Contacts                    []string
+	Users                       []*groupUser
+}
+
+type groupUser struct {
+	DisplayName          string `json:"displayName"`
+	EmailAddress         string `json:"emailAddress,omitempty"`
+	GroupUserAccessRight string `json:"groupUserAccessRight"`
+	Identifier           string `json:"identifier"`
+	PrincipalType        string `json:"principalType"`
+}
+
+type groupJSON struct {
+	ID                    string `json:"id"`
+	IsReadOnly            bool   `json:"isReadOnly"`
+	IsOnDedicatedCapacity bool   `json:"isOnDedicatedCapacity"`
+	Name                  string `json:"name"`
+	CapacityID            string `json:"capacityId,omitempty"`
+}
+
+func (g *group) toJSON() groupJSON {
+	return groupJSON{
+		ID:                    g.ID,
+		IsOnDedicatedCapacity: g.CapacityID != "",
+		Name:                  g.Name,
+		CapacityID:            g.CapacityID,
+	}
+}
+
+type adminGroupJSON struct {
+	ID                          string   `json:"id"`
+	Name                        string   `json:"name"`
+	Type                        string   `json:"type"`
+	State                       string   `json:"state"`
+	IsReadOnly                  bool     `json:"isReadOnly"`
+	IsOnDedicatedCapacity       bool     `json:"isOnDedicatedCapacity"`
+	CapacityID                  string   `json:"capacityId,omitempty"`
+	Description                 string   `json:"description,omitempty"`
+	DefaultDatasetStorageFormat string   `json:"defaultDatasetStorageFormat"`
+	Contacts                    []string `json:"contacts"`
+}
+
+func (g *group) toAdminJSON() adminGroupJSON {
+	return adminGroupJSON{
+		ID:                          g.ID,
+		Name:                        g.Name,
+		Type:                        "Workspace",
+		State:                       "Active",
+		IsOnDedicatedCapacity:       g.CapacityID != "",
+		CapacityID:                  g.CapacityID,
+		Description:                 g.Description,
+		DefaultDatasetStorageFormat: g.DefaultDatasetStorageFormat,
+		Contacts:                    g.Contacts,
+	}
+}
+
+func (server *Server) findGroup(groupID string) *group {
+	for _, g := range server.groups {
+		if strings.EqualFold(g.ID, groupID) {
+			return g
+		}
+	}
+	return nil
+}
+
+// findGroupOrNotFound finds a group, writing a not found response if it does not exist
+func (server *Server) findGroupOrNotFound(w http.ResponseWriter, groupID string) *group {
+	g := server.findGroup(groupID)
+	if g == nil {
+		writeNotFound(w, "group", groupID)
+	}
+	return g
+}
+
+func (server *Server) createGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	var request struct {
+		Name string `json:"name"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "Workspace name is required")
+		return
+	}
+	for _, g := range server.groups {
+		if strings.EqualFold(g.Name, request.Name) {
+			writeError(w, http.StatusConflict, "PowerBIEntityAlreadyExists", fmt.Sprintf("Workspace '%s' already exists", request.Name))
+			return
+		}
+	}
+
+	g := &group{
+		ID:                          newID(),
+		Name:                        request.Name,
+		DefaultDatasetStorageFormat: "Small",
+		Contacts:                    []string{},
+	}
+	server.groups = append(server.groups, g)
+	writeJSON(w, g.toJSON())
+}
+
+func (server *Server) getGroups(w http.ResponseWriter, r *http.Request, params []string) {
+	items := make([]interface{}, 0, len(server.groups))
+	for _, g := range server.groups {
+		items = append(items, g.toJSON())
+	}
+
+	items, err := applyOData(r.URL.Query(), items)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) deleteGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	groups := server.groups[:0]
+	for _, existing := range server.groups {
+		if existing != g {
+			groups = append(groups, existing)
+		}
+	}
+	server.groups = groups
+
+	// remove everything that was inside the group
+	datasets := server.datasets[:0]
+	for _, d := range server.datasets {
+		if d.GroupID != g.ID {
+			datasets = append(datasets, d)
+		}
+	}
+	server.datasets = datasets
+
+	reports := server.reports[:0]
+	for _, rp := range server.reports {
+		if rp.GroupID != g.ID {
+			reports = append(reports, rp)
+		}
+	}
+	server.reports = reports
+
+	imports := server.imports[:0]
+	for _, im := range server.imports {
+		if im.GroupID != g.ID {
+			imports = append(imports, im)
+		}
+	}
+	server.imports = imports
+
+	dataflows := server.dataflows[:0]
+	for _, df := range server.dataflows {
+		if df.GroupID != g.ID {
+			dataflows = append(dataflows, df)
+		}
+	}
+	server.dataflows = dataflows
+}
+
+func (server *Server) updateGroupAsAdmin(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	var request struct {
+		Name                        *string   `json:"name"`
+		Description                 *string   `json:"description"`
+		DefaultDatasetStorageFormat *string   `json:"defaultDatasetStorageFormat"`
+		Contacts                    *[]string `json:"contacts"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.DefaultDatasetStorageFormat != nil && *request.DefaultDatasetStorageFormat != "Small" && *request.DefaultDatasetStorageFormat != "Large" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Unsupported defaultDatasetStorageFormat '%s'", *request.DefaultDatasetStorageFormat))
+		return
+	}
+
+	if request.Name != nil {
+		g.Name = *request.Name
+	}
+	if request.Description != nil {
+		g.Description = *request.Description
+	}
+	if request.DefaultDatasetStorageFormat != nil {
+		g.DefaultDatasetStorageFormat = *request.DefaultDatasetStorageFormat
+	}
+	if request.Contacts != nil {
+		g.Contacts = *request.Contacts
+	}
+}
+
+func (server *Server) getGroupsAsAdmin(w http.ResponseWriter, r *http.Request, params []string) {
+	// unlike the non-admin API, $top is required
+	if r.URL.Query().Get("$top") == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "$top is required")
+		return
+	}
+
+	items := make([]interface{}, 0, len(server.groups))
+	for _, g := range server.groups {
+		items = append(items, g.toAdminJSON())
+	}
+
+	items, err := applyOData(r.URL.Query(), items)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) getGroupUsers(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+	writeJSON(w, valueResponse{Value: g.Users})
+}
+
+func (server *Server) addGroupUser(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	var request groupUser
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Identifier == "" {
+		request.Identifier = request.EmailAddress
+	}
+	if request.Identifier == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "Either identifier or emailAddress is required")
+		return
+	}
+	if findGroupUser(g, request.Identifier) != nil {
+		writeError(w, http.StatusConflict, "AddingAlreadyExistsGroupUserNotSupportedError", fmt.Sprintf("User '%s' already has access to the workspace", request.Identifier))
+		return
+	}
+
+	g.Users = append(g.Users, &request)
+}
+
+func (server *Server) updateGroupUser(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	var request groupUser
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	identifier := request.Identifier
+	if identifier == "" {
+		identifier = request.EmailAddress
+	}
+	user := findGroupUser(g, identifier)
+	if user == nil {
+		writeNotFound(w, "user", identifier)
+		return
+	}
+	user.GroupUserAccessRight = request.GroupUserAccessRight
+}
+
+func (server *Server) deleteUserInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	user := findGroupUser(g, params[1])
+	if user == nil {
+		writeNotFound(w, "user", params[1])
+		return
+	}
+
+	users := g.Users[:0]
+	for _, existing := range g.Users {
+		if existing != user {
+			users = append(users, existing)
+		}
+	}
+	g.Users = users
+}
+
+func (server *Server) refreshUserPermissions(w http.ResponseWriter, r *http.Request, params []string) {
+}
+
+func findGroupUser(g *group, identifier string) *groupUser {
+	for _, user := range g.Users {
+		if strings.EqualFold(user.Identifier, identifier) || (user.EmailAddress != "" && strings.EqualFold(user.EmailAddress, identifier)) {
+			return user
+		}
+	}
+	return nil
+}
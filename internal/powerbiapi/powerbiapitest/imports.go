@@ -0,0 +1,345 @@
+package powerbiapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type importItem struct {
+	ID          string
+	GroupID     string
+	Name        string
+	ImportState string
+	Created     time.Time
+	Updated     time.Time
+	DatasetIDs  []string
+	ReportIDs   []string
+	Content     []byte
+}
+
+type importJSON struct {
+	ID              string              `json:"id"`
+	ImportState     string              `json:"importState"`
+	CreatedDateTime time.Time           `json:"createdDateTime"`
+	UpdatedDateTime time.Time           `json:"updatedDateTime"`
+	Name            string              `json:"name"`
+	ConnectionType  string              `json:"connectionType"`
+	Source          string              `json:"source"`
+	Datasets        []importDatasetJSON `json:"datasets"`
+	Reports         []importReportJSON  `json:"reports"`
+}
+
+type importDatasetJSON struct {
+	ID                string `json:"id"`
+	Name              string `json:"name"`
+	WebURL            string `json:"webUrl"`
+	TargetStorageMode string `json:"targetStorageMode"`
+}
+
+type importReportJSON struct {
+	ID         string `json:"id"`
+	ReportType string `json:"reportType"`
+	Name       string `json:"name"`
+	WebURL     string `json:"webUrl"`
+}
+
+// ImportContent returns the file content uploaded by an import
+func (server *Server) ImportContent(importID string) []byte {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	for _, im := range server.imports {
+		if strings.EqualFold(im.ID, importID) {
+			return im.Content
+		}
+	}
+	return nil
+}
+
+func (server *Server) importToJSON(im *importItem) importJSON {
+	result := importJSON{
+		ID:              im.ID,
+		ImportState:     im.ImportState,
+		CreatedDateTime: im.Created,
+		UpdatedDateTime: im.Updated,
+		Name:            im.Name,
+		ConnectionType:  "import",
+		Source:          "Upload",
+		Datasets:        make([]importDatasetJSON, 0),
+		Reports:         make([]importReportJSON, 0),
+	}
+
+	// datasets and reports deleted since the import are no longer returned
+	for _, datasetID := range im.DatasetIDs {
+		if d := server.findDataset(im.GroupID, datasetID); d != nil {
+			datasetJSON := d.toJSON()
+			result.Datasets = append(result.Datasets, importDatasetJSON{
+				ID:                datasetJSON.ID,
+				Name:              datasetJSON.Name,
+				WebURL:            datasetJSON.WebURL,
+				TargetStorageMode: datasetJSON.TargetStorageMode,
+			})
+		}
+	}
+	for _, reportID := range im.ReportIDs {
+		if rp := server.findReport(im.GroupID, reportID); rp != nil {
+			reportJSON := rp.toJSON()
+			result.Reports = append(result.Reports, importReportJSON{
+				ID:         reportJSON.ID,
+				ReportType: reportJSON.ReportType,
+				Name:       reportJSON.Name,
+				WebURL:     reportJSON.WebURL,
+			})
+		}
+	}
+	return result
+}
+
+func (server *Server) postImportInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	query := r.URL.Query()
+	name := query.Get("datasetDisplayName")
+	nameConflict := query.Get("nameConflict")
+	skipReport := strings.EqualFold(query.Get("skipReport"), "true")
+	if name == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "datasetDisplayName is required")
+		return
+	}
+
+	var content []byte
+	var err error
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		content, err = server.readImportFileURLContent(r)
+	} else {
+		content, err = readMultipartContent(r)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return
+	}
+
+	switch {
+	case strings.EqualFold(name, "model.json"):
+		server.importDataflow(w, g, nameConflict, content)
+		return
+	case strings.HasSuffix(strings.ToLower(name), ".rdl"):
+		server.importPaginatedReport(w, g, name, nameConflict, content)
+		return
+	}
+
+	existingDataset, existingReport := server.findImportedArtifacts(g.ID, name)
+	hasExisting := existingDataset != nil || existingReport != nil
+
+	switch nameConflict {
+	case "Abort":
+		if hasExisting {
+			writeError(w, http.StatusConflict, "DuplicatePackageError", fmt.Sprintf("Content named '%s' already exists", name))
+			return
+		}
+	case "Overwrite":
+		if !hasExisting {
+			writeError(w, http.StatusConflict, "DuplicatePackageNotFoundError", fmt.Sprintf("No content named '%s' exists to overwrite", name))
+			return
+		}
+	case "", "Ignore":
+		existingDataset, existingReport = nil, nil
+	case "CreateOrOverwrite":
+	case "GenerateUniqueName":
+		for i := 2; hasExisting; i++ {
+			uniqueName := fmt.Sprintf("%s (%d)", name, i)
+			existingDataset, existingReport = server.findImportedArtifacts(g.ID, uniqueName)
+			hasExisting = existingDataset != nil || existingReport != nil
+			name = uniqueName
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Unsupported nameConflict '%s'", nameConflict))
+		return
+	}
+
+	d := existingDataset
+	if d == nil {
+		d = newDataset(g.ID, name)
+		server.datasets = append(server.datasets, d)
+	}
+	server.resetContent(d)
+
+	im := newImportItem(g.ID, name, content)
+	im.DatasetIDs = []string{d.ID}
+
+	if !skipReport {
+		rp := existingReport
+		if rp == nil {
+			rp = newReport(g.ID, name, d.ID)
+			server.reports = append(server.reports, rp)
+		}
+		rp.DatasetID = d.ID
+		im.ReportIDs = []string{rp.ID}
+	}
+
+	server.imports = append(server.imports, im)
+	writeJSON(w, map[string]string{"id": im.ID})
+}
+
+// importPaginatedReport imports an .rdl file, which only creates a paginated report named without the extension
+func (server *Server) importPaginatedReport(w http.ResponseWriter, g *group, name string, nameConflict string, content []byte) {
+	reportName := name[:len(name)-len(".rdl")]
+
+	var existingReport *report
+	for _, rp := range server.reports {
+		if strings.EqualFold(rp.GroupID, g.ID) && strings.EqualFold(rp.Name, reportName) && rp.ReportType == "PaginatedReport" {
+			existingReport = rp
+			break
+		}
+	}
+
+	switch nameConflict {
+	case "Abort":
+		if existingReport != nil {
+			writeError(w, http.StatusConflict, "DuplicatePackageError", fmt.Sprintf("Content named '%s' already exists", name))
+			return
+		}
+	case "Overwrite":
+		if existingReport == nil {
+			writeError(w, http.StatusConflict, "DuplicatePackageNotFoundError", fmt.Sprintf("No content named '%s' exists to overwrite", name))
+			return
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("nameConflict '%s' is not supported for .rdl files", nameConflict))
+		return
+	}
+
+	rp := existingReport
+	if rp == nil {
+		rp = newReport(g.ID, reportName, "")
+		rp.ReportType = "PaginatedReport"
+		server.reports = append(server.reports, rp)
+	}
+
+	im := newImportItem(g.ID, name, content)
+	im.ReportIDs = []string{rp.ID}
+
+	server.imports = append(server.imports, im)
+	writeJSON(w, map[string]string{"id": im.ID})
+}
+
+// importDataflow imports a dataflow model.json, which creates a dataflow named by the model
+func (server *Server) importDataflow(w http.ResponseWriter, g *group, nameConflict string, content []byte) {
+	var model struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(content, &model); err != nil || model.Name == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "model.json is not a valid dataflow model")
+		return
+	}
+
+	name := model.Name
+	switch nameConflict {
+	case "Abort":
+		if server.findDataflowByName(g.ID, name) != nil {
+			writeError(w, http.StatusConflict, "DuplicatePackageError", fmt.Sprintf("Dataflow named '%s' already exists", name))
+			return
+		}
+	case "GenerateUniqueName":
+		for i := 2; server.findDataflowByName(g.ID, name) != nil; i++ {
+			name = fmt.Sprintf("%s (%d)", model.Name, i)
+		}
+	default:
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("nameConflict '%s' is not supported for dataflows", nameConflict))
+		return
+	}
+
+	server.dataflows = append(server.dataflows, &dataflow{
+		ID:          newID(),
+		GroupID:     g.ID,
+		Name:        name,
+		Description: model.Description,
+	})
+
+	im := newImportItem(g.ID, name, content)
+	server.imports = append(server.imports, im)
+	writeJSON(w, map[string]string{"id": im.ID})
+}
+
+func newImportItem(groupID string, name string, content []byte) *importItem {
+	now := time.Now().UTC()
+	return &importItem{
+		ID:          newID(),
+		GroupID:     groupID,
+		Name:        name,
+		ImportState: "Succeeded",
+		Created:     now,
+		Updated:     now,
+		Content:     content,
+	}
+}
+
+func (server *Server) getImportsInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findGroupOrNotFound(w, params[0]) == nil {
+		return
+	}
+
+	items := make([]importJSON, 0)
+	for _, im := range server.imports {
+		if strings.EqualFold(im.GroupID, params[0]) {
+			items = append(items, server.importToJSON(im))
+		}
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) getImportInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findGroupOrNotFound(w, params[0]) == nil {
+		return
+	}
+
+	for _, im := range server.imports {
+		if strings.EqualFold(im.GroupID, params[0]) && strings.EqualFold(im.ID, params[1]) {
+			writeJSON(w, server.importToJSON(im))
+			return
+		}
+	}
+	writeNotFound(w, "import", params[1])
+}
+
+// findImportedArtifacts finds the dataset and report that an import with the given name would overwrite
+func (server *Server) findImportedArtifacts(groupID string, name string) (*dataset, *report) {
+	var foundDataset *dataset
+	var foundReport *report
+	for _, d := range server.datasets {
+		if strings.EqualFold(d.GroupID, groupID) && strings.EqualFold(d.Name, name) && !d.AddRowsAPIEnabled {
+			foundDataset = d
+			break
+		}
+	}
+	for _, rp := range server.reports {
+		if strings.EqualFold(rp.GroupID, groupID) && strings.EqualFold(rp.Name, name) {
+			foundReport = rp
+			break
+		}
+	}
+	return foundDataset, foundReport
+}
+
+func readMultipartContent(r *http.Request) ([]byte, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+
+	part, err := reader.NextPart()
+	if err != nil {
+		return nil, fmt.Errorf("Expected a file in the multipart body: %s", err)
+	}
+	defer part.Close()
+
+	return ioutil.ReadAll(part)
+}
@@ -0,0 +1,131 @@
+package powerbiapitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	odataAndRegex        = regexp.MustCompile(`(?i)\s+and\s+`)
+	odataComparisonRegex = regexp.MustCompile(`(?i)^\s*(\w+)\s+(eq|ne)\s+(?:'((?:[^']|'')*)'|(true|false))\s*$`)
+	odataFunctionRegex   = regexp.MustCompile(`(?i)^\s*(contains|startswith|endswith)\(\s*(\w+)\s*,\s*'((?:[^']|'')*)'\s*\)\s*$`)
+)
+
+type odataPredicate func(item map[string]interface{}) bool
+
+// parseODataFilter supports the subset of OData $filter used against Power BI, being
+// eq, ne, contains, startswith and endswith clauses joined by and
+func parseODataFilter(filter string) (odataPredicate, error) {
+	if strings.TrimSpace(filter) == "" {
+		return func(item map[string]interface{}) bool { return true }, nil
+	}
+
+	var predicates []odataPredicate
+	for _, clause := range odataAndRegex.Split(filter, -1) {
+		predicate, err := parseODataClause(clause)
+		if err != nil {
+			return nil, err
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	return func(item map[string]interface{}) bool {
+		for _, predicate := range predicates {
+			if !predicate(item) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+func parseODataClause(clause string) (odataPredicate, error) {
+	if matches := odataComparisonRegex.FindStringSubmatch(clause); matches != nil {
+		field, operator := matches[1], strings.ToLower(matches[2])
+		expected := strings.ReplaceAll(matches[3], "''", "'")
+		if matches[4] != "" {
+			expected = strings.ToLower(matches[4])
+		}
+		return func(item map[string]interface{}) bool {
+			isEqual := strings.EqualFold(odataFieldValue(item, field), expected)
+			return isEqual == (operator == "eq")
+		}, nil
+	}
+
+	if matches := odataFunctionRegex.FindStringSubmatch(clause); matches != nil {
+		function, field := strings.ToLower(matches[1]), matches[2]
+		expected := strings.ToLower(strings.ReplaceAll(matches[3], "''", "'"))
+		return func(item map[string]interface{}) bool {
+			value := strings.ToLower(odataFieldValue(item, field))
+			switch function {
+			case "contains":
+				return strings.Contains(value, expected)
+			case "startswith":
+				return strings.HasPrefix(value, expected)
+			default:
+				return strings.HasSuffix(value, expected)
+			}
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported $filter clause '%s'", clause)
+}
+
+func odataFieldValue(item map[string]interface{}, field string) string {
+	for key, value := range item {
+		if strings.EqualFold(key, field) {
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return ""
+}
+
+// applyOData filters and pages items using the $filter, $top and $skip query parameters
+func applyOData(query url.Values, items []interface{}) ([]interface{}, error) {
+	predicate, err := parseODataFilter(query.Get("$filter"))
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		var itemMap map[string]interface{}
+		if err := json.Unmarshal(data, &itemMap); err != nil {
+			return nil, err
+		}
+		if predicate(itemMap) {
+			filtered = append(filtered, item)
+		}
+	}
+
+	if skipValue := query.Get("$skip"); skipValue != "" {
+		skip, err := strconv.Atoi(skipValue)
+		if err != nil || skip < 0 {
+			return nil, fmt.Errorf("Invalid $skip '%s'", skipValue)
+		}
+		if skip > len(filtered) {
+			skip = len(filtered)
+		}
+		filtered = filtered[skip:]
+	}
+
+	if topValue := query.Get("$top"); topValue != "" {
+		top, err := strconv.Atoi(topValue)
+		if err != nil || top < 0 {
+			return nil, fmt.Errorf("Invalid $top '%s'", topValue)
+		}
+		if top < len(filtered) {
+			filtered = filtered[:top]
+		}
+	}
+
+	return filtered, nil
+}
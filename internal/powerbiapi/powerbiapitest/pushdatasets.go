@@ -0,0 +1,93 @@
+package powerbiapitest
+
+import (
+	"net/http"
+)
+
+func (server *Server) postDatasetInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	g := server.findGroupOrNotFound(w, params[0])
+	if g == nil {
+		return
+	}
+
+	var request struct {
+		Name        string   `json:"name"`
+		DefaultMode string   `json:"defaultMode"`
+		Tables      []*table `json:"tables"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	switch request.DefaultMode {
+	case "", "Push", "PushStreaming", "Streaming":
+	default:
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "Unsupported defaultMode '"+request.DefaultMode+"'")
+		return
+	}
+
+	d := newDataset(g.ID, request.Name)
+	d.AddRowsAPIEnabled = true
+	d.IsRefreshable = false
+	d.TargetStorageMode = "PushStreaming"
+	d.Tables = request.Tables
+	server.datasets = append(server.datasets, d)
+
+	writeJSON(w, map[string]string{
+		"id":   d.ID,
+		"name": d.Name,
+	})
+}
+
+func (server *Server) getTables(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDataset("", params[0])
+	if d == nil {
+		writeNotFound(w, "dataset", params[0])
+		return
+	}
+	writeJSON(w, valueResponse{Value: d.Tables})
+}
+
+func (server *Server) putTableInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request table
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	for _, t := range d.Tables {
+		if t.Name == params[2] {
+			t.Columns = request.Columns
+			t.Measures = request.Measures
+			writeJSON(w, t)
+			return
+		}
+	}
+	writeNotFound(w, "table", params[2])
+}
+
+func (server *Server) postRowsInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request struct {
+		Rows []map[string]interface{}
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	for _, t := range d.Tables {
+		if t.Name == params[2] {
+			t.RowCount += len(request.Rows)
+			return
+		}
+	}
+	writeNotFound(w, "table", params[2])
+}
@@ -0,0 +1,247 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type refresh struct {
+	RequestID            string    `json:"requestId"`
+	ID                   int64     `json:"id"`
+	RefreshType          string    `json:"refreshType"`
+	StartTime            time.Time `json:"startTime"`
+	EndTime              time.Time `json:"endTime"`
+	Status               string    `json:"status"`
+	ServiceExceptionJSON string    `json:"serviceExceptionJson,omitempty"`
+
+	// hiddenReads is how many more times the refresh history is read before the refresh appears in it
+	hiddenReads int
+
+	// enhanced refresh settings, only reported by the execution details
+	Type           string          `json:"-"`
+	CommitMode     string          `json:"-"`
+	MaxParallelism int             `json:"-"`
+	RetryCount     int             `json:"-"`
+	Objects        []refreshObject `json:"-"`
+}
+
+type refreshObject struct {
+	Table     string `json:"table"`
+	Partition string `json:"partition,omitempty"`
+	Status    string `json:"status"`
+}
+
+type refreshMessage struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+type refreshExecutionDetailsJSON struct {
+	StartTime          time.Time        `json:"startTime"`
+	EndTime            time.Time        `json:"endTime"`
+	Type               string           `json:"type"`
+	CommitMode         string           `json:"commitMode"`
+	Status             string           `json:"status"`
+	ExtendedStatus     string           `json:"extendedStatus"`
+	CurrentRefreshType string           `json:"currentRefreshType"`
+	NumberOfAttempts   int              `json:"numberOfAttempts"`
+	Objects            []refreshObject  `json:"objects"`
+	Messages           []refreshMessage `json:"messages,omitempty"`
+}
+
+func (rf *refresh) isEnhanced() bool {
+	return rf.RefreshType == "ViaEnhancedApi"
+}
+
+func (rf *refresh) toExecutionDetailsJSON() refreshExecutionDetailsJSON {
+	result := refreshExecutionDetailsJSON{
+		StartTime:          rf.StartTime,
+		EndTime:            rf.EndTime,
+		Type:               rf.Type,
+		CommitMode:         rf.CommitMode,
+		Status:             rf.Status,
+		ExtendedStatus:     rf.Status,
+		CurrentRefreshType: rf.Type,
+		NumberOfAttempts:   1,
+		Objects:            make([]refreshObject, 0, len(rf.Objects)),
+	}
+	if rf.Status == "Unknown" {
+		result.ExtendedStatus = "InProgress"
+	}
+	for _, object := range rf.Objects {
+		object.Status = result.ExtendedStatus
+		result.Objects = append(result.Objects, object)
+	}
+	if rf.ServiceExceptionJSON != "" {
+		result.Messages = []refreshMessage{{Code: "ModelRefreshFailed", Message: rf.ServiceExceptionJSON, Type: "Error"}}
+	}
+	return result
+}
+
+// progressRefresh completes an in progress refresh, as if the service had finished processing it
+func (server *Server) progressRefresh(rf *refresh) {
+	if rf.Status != "Unknown" || server.HoldRefreshes {
+		return
+	}
+
+	rf.EndTime = time.Now().UTC()
+	rf.Status = "Completed"
+	if server.RefreshServiceException != "" {
+		rf.Status = "Failed"
+		rf.ServiceExceptionJSON = server.RefreshServiceException
+	}
+}
+
+func (server *Server) findRefreshOrNotFound(w http.ResponseWriter, d *dataset, refreshID string) *refresh {
+	for _, rf := range d.Refreshes {
+		if rf.isEnhanced() && strings.EqualFold(rf.RequestID, refreshID) {
+			return rf
+		}
+	}
+	writeNotFound(w, "refresh", refreshID)
+	return nil
+}
+
+func (server *Server) refreshDatasetInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	var request struct {
+		NotifyOption       string          `json:"notifyOption"`
+		Type               string          `json:"type"`
+		CommitMode         string          `json:"commitMode"`
+		MaxParallelism     int             `json:"maxParallelism"`
+		RetryCount         int             `json:"retryCount"`
+		Objects            []refreshObject `json:"objects"`
+		ApplyRefreshPolicy *bool           `json:"applyRefreshPolicy"`
+		EffectiveDate      string          `json:"effectiveDate"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if !d.IsRefreshable {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Dataset '%s' is not refreshable", d.ID))
+		return
+	}
+	if request.NotifyOption != "" && request.NotifyOption != "NoNotification" && request.NotifyOption != "MailOnFailure" && request.NotifyOption != "MailOnCompletion" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Unsupported notifyOption '%s'", request.NotifyOption))
+		return
+	}
+
+	// refreshes are reported as in progress until they are next read
+	rf := &refresh{
+		RequestID:   newID(),
+		ID:          int64(len(d.Refreshes) + 1),
+		RefreshType: "ViaApi",
+		StartTime:   time.Now().UTC(),
+		Status:      "Unknown",
+		hiddenReads: server.RefreshHistoryDelay,
+	}
+
+	// any setting other than notifyOption makes the refresh an enhanced refresh
+	isEnhanced := request.Type != "" || request.CommitMode != "" || request.MaxParallelism != 0 || request.RetryCount != 0 ||
+		request.Objects != nil || request.ApplyRefreshPolicy != nil || request.EffectiveDate != ""
+	if isEnhanced {
+		if request.NotifyOption != "" {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", "notifyOption is not supported by enhanced refreshes")
+			return
+		}
+		if g := server.findGroup(params[0]); g.CapacityID == "" || g.CapacityID == unassignedCapacityID {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", "Enhanced refresh is only supported for workspaces on a dedicated capacity")
+			return
+		}
+
+		rf.RefreshType = "ViaEnhancedApi"
+		rf.Type = request.Type
+		if rf.Type == "" {
+			rf.Type = "Automatic"
+		}
+		rf.CommitMode = request.CommitMode
+		if rf.CommitMode == "" {
+			rf.CommitMode = "Transactional"
+		}
+		rf.MaxParallelism = request.MaxParallelism
+		rf.RetryCount = request.RetryCount
+		rf.Objects = request.Objects
+
+		location := url.URL{Path: fmt.Sprintf("%s/groups/%s/datasets/%s/refreshes/%s", apiPathPrefix, params[0], params[1], rf.RequestID)}
+		w.Header().Set("Location", server.URL+location.EscapedPath())
+	}
+
+	d.Refreshes = append([]*refresh{rf}, d.Refreshes...)
+
+	if !server.OmitRefreshRequestID {
+		w.Header().Set("RequestId", rf.RequestID)
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (server *Server) getRefreshHistoryInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+
+	top := len(d.Refreshes)
+	if topParam := r.URL.Query().Get("$top"); topParam != "" {
+		var err error
+		if top, err = strconv.Atoi(topParam); err != nil || top < 1 {
+			writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Invalid $top '%s'", topParam))
+			return
+		}
+	}
+
+	items := make([]refresh, 0)
+	for _, rf := range d.Refreshes {
+		if rf.hiddenReads > 0 {
+			rf.hiddenReads--
+			continue
+		}
+		if len(items) >= top {
+			break
+		}
+		items = append(items, *rf)
+		server.progressRefresh(rf)
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) getRefreshExecutionDetailsInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	rf := server.findRefreshOrNotFound(w, d, params[2])
+	if rf == nil {
+		return
+	}
+
+	writeJSON(w, rf.toExecutionDetailsJSON())
+	server.progressRefresh(rf)
+}
+
+func (server *Server) cancelRefreshInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	d := server.findDatasetOrNotFound(w, params[0], params[1])
+	if d == nil {
+		return
+	}
+	rf := server.findRefreshOrNotFound(w, d, params[2])
+	if rf == nil {
+		return
+	}
+
+	if rf.Status != "Unknown" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", fmt.Sprintf("Refresh '%s' is not in progress", rf.RequestID))
+		return
+	}
+	rf.EndTime = time.Now().UTC()
+	rf.Status = "Cancelled"
+}
@@ -0,0 +1,184 @@
+package powerbiapitest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+type report struct {
+	ID         string
+	GroupID    string
+	Name       string
+	DatasetID  string
+	ReportType string
+}
+
+type reportJSON struct {
+	ID         string `json:"id"`
+	ReportType string `json:"reportType"`
+	Name       string `json:"name"`
+	DatasetID  string `json:"datasetId"`
+	WebURL     string `json:"webUrl"`
+	EmbedURL   string `json:"embedUrl"`
+}
+
+func newReport(groupID string, name string, datasetID string) *report {
+	return &report{
+		ID:         newID(),
+		GroupID:    groupID,
+		Name:       name,
+		DatasetID:  datasetID,
+		ReportType: "PowerBIReport",
+	}
+}
+
+func (rp *report) toJSON() reportJSON {
+	return reportJSON{
+		ID:         rp.ID,
+		ReportType: rp.ReportType,
+		Name:       rp.Name,
+		DatasetID:  rp.DatasetID,
+		WebURL:     fmt.Sprintf("https://app.powerbi.com/groups/%s/reports/%s", rp.GroupID, rp.ID),
+		EmbedURL:   fmt.Sprintf("https://app.powerbi.com/reportEmbed?reportId=%s&groupId=%s", rp.ID, rp.GroupID),
+	}
+}
+
+func (server *Server) findReport(groupID string, reportID string) *report {
+	for _, rp := range server.reports {
+		if strings.EqualFold(rp.ID, reportID) && strings.EqualFold(rp.GroupID, groupID) {
+			return rp
+		}
+	}
+	return nil
+}
+
+// findReportOrNotFound finds a report, writing a not found response if it does not exist
+func (server *Server) findReportOrNotFound(w http.ResponseWriter, groupID string, reportID string) *report {
+	if server.findGroupOrNotFound(w, groupID) == nil {
+		return nil
+	}
+	rp := server.findReport(groupID, reportID)
+	if rp == nil {
+		writeNotFound(w, "report", reportID)
+	}
+	return rp
+}
+
+func (server *Server) getReportsInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findGroupOrNotFound(w, params[0]) == nil {
+		return
+	}
+
+	items := make([]reportJSON, 0)
+	for _, rp := range server.reports {
+		if strings.EqualFold(rp.GroupID, params[0]) {
+			items = append(items, rp.toJSON())
+		}
+	}
+	writeJSON(w, valueResponse{Value: items})
+}
+
+func (server *Server) getReportInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	rp := server.findReportOrNotFound(w, params[0], params[1])
+	if rp == nil {
+		return
+	}
+	writeJSON(w, rp.toJSON())
+}
+
+func (server *Server) deleteReportInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	rp := server.findReportOrNotFound(w, params[0], params[1])
+	if rp == nil {
+		return
+	}
+
+	reports := server.reports[:0]
+	for _, existing := range server.reports {
+		if existing != rp {
+			reports = append(reports, existing)
+		}
+	}
+	server.reports = reports
+}
+
+func (server *Server) rebindReportInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	rp := server.findReportOrNotFound(w, params[0], params[1])
+	if rp == nil {
+		return
+	}
+
+	var request struct {
+		DatasetID string `json:"datasetId"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	d := server.findDataset("", request.DatasetID)
+	if d == nil {
+		writeNotFound(w, "dataset", request.DatasetID)
+		return
+	}
+	rp.DatasetID = d.ID
+}
+
+func (server *Server) cloneReportInGroup(w http.ResponseWriter, r *http.Request, params []string) {
+	source := server.findReportOrNotFound(w, params[0], params[1])
+	if source == nil {
+		return
+	}
+
+	var request struct {
+		Name              string `json:"name"`
+		TargetModelID     string `json:"targetModelId"`
+		TargetWorkspaceID string `json:"targetWorkspaceId"`
+	}
+	if !readJSON(w, r, &request) {
+		return
+	}
+
+	if request.Name == "" {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", "Report name is required")
+		return
+	}
+
+	groupID := source.GroupID
+	if request.TargetWorkspaceID != "" {
+		target := server.findGroupOrNotFound(w, request.TargetWorkspaceID)
+		if target == nil {
+			return
+		}
+		groupID = target.ID
+	}
+
+	datasetID := source.DatasetID
+	if request.TargetModelID != "" {
+		d := server.findDataset("", request.TargetModelID)
+		if d == nil {
+			writeNotFound(w, "dataset", request.TargetModelID)
+			return
+		}
+		datasetID = d.ID
+	}
+
+	rp := newReport(groupID, request.Name, datasetID)
+	server.reports = append(server.reports, rp)
+	writeJSON(w, rp.toJSON())
+}
+
+func (server *Server) updateFabricReport(w http.ResponseWriter, r *http.Request, params []string) {
+	rp := server.findReportOrNotFound(w, params[0], params[1])
+	if rp == nil {
+		return
+	}
+
+	var request fabricItemUpdate
+	if !readJSON(w, r, &request) {
+		return
+	}
+	if request.DisplayName != nil {
+		rp.Name = *request.DisplayName
+	}
+	writeJSON(w, fabricItemJSON{ID: rp.ID, Type: "Report", DisplayName: rp.Name, WorkspaceID: rp.GroupID})
+}
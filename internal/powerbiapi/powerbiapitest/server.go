@@ -0,0 +1,466 @@
+// Package powerbiapitest provides an in-process fake of the Power BI REST API
+// for testing code that uses the powerbiapi package without a real tenant.
+package powerbiapitest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+const (
+	apiPathPrefix    = "/v1.0/myorg"
+	fabricPathPrefix = "/v1/"
+	msiPath          = "/metadata/identity/oauth2/token"
+	testTenantID     = "00000000-0000-0000-0000-00000000000a"
+	testClientID     = "00000000-0000-0000-0000-00000000000b"
+)
+
+// Server is a fake Power BI service. The zero value is not usable, create servers with NewServer
+type Server struct {
+	*httptest.Server
+
+	// DefaultParameters are given to every dataset created by an import
+	DefaultParameters []Parameter
+
+	// DefaultDatasources are given to every dataset created by an import
+	DefaultDatasources []Datasource
+
+	// RefreshServiceException, when set, causes dataset refreshes to fail with this service exception JSON
+	RefreshServiceException string
+
+	// HoldRefreshes, when set, keeps dataset refreshes in progress until they are cancelled
+	HoldRefreshes bool
+
+	// OmitRefreshRequestID, when set, leaves the RequestId header off the response to a dataset refresh
+	OmitRefreshRequestID bool
+
+	// RefreshHistoryDelay is how many times the refresh history is read before a new refresh appears in it, as the
+	// service can take a while to record a refresh
+	RefreshHistoryDelay int
+
+	// TokenLifetime is how long issued access tokens are valid for. Defaults to an hour
+	TokenLifetime time.Duration
+
+	mux          sync.Mutex
+	throttled    int
+	routes       []route
+	fabricRoutes []route
+	tokens       map[string]time.Time
+	tokensIssued int
+	tokenRequest url.Values
+	groups       []*group
+	datasets     []*dataset
+	reports      []*report
+	imports      []*importItem
+	dataflows    []*dataflow
+	uploads      []*temporaryUpload
+	capacities   []*Capacity
+	gateways     []*Gateway
+	gatewayKey   *rsa.PrivateKey
+}
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+// Parameter represents a dataset parameter
+type Parameter struct {
+	Name         string `json:"name"`
+	Type         string `json:"type"`
+	IsRequired   bool   `json:"isRequired"`
+	CurrentValue string `json:"currentValue"`
+}
+
+// Datasource represents a dataset datasource
+type Datasource struct {
+	DatasourceID      string            `json:"datasourceId"`
+	DatasourceType    string            `json:"datasourceType"`
+	GatewayID         string            `json:"gatewayId,omitempty"`
+	ConnectionDetails map[string]string `json:"connectionDetails"`
+	CredentialType    string            `json:"-"`
+	Credentials       string            `json:"-"`
+	// Cloud datasources do not need an on-premises gateway and remain on their cloud gateway when the dataset is bound
+	Cloud bool `json:"-"`
+}
+
+type errorResponse struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type valueResponse struct {
+	Value interface{} `json:"value"`
+}
+
+// fabricItemUpdate is the request to update a Fabric item such as a report or semantic model
+type fabricItemUpdate struct {
+	DisplayName *string `json:"displayName"`
+	Description *string `json:"description"`
+}
+
+type fabricItemJSON struct {
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	DisplayName string `json:"displayName"`
+	WorkspaceID string `json:"workspaceId"`
+}
+
+// NewServer starts a fake Power BI service. The caller should call Close when finished
+func NewServer() *Server {
+	server := &Server{
+		tokens: make(map[string]time.Time),
+	}
+	server.registerRoutes()
+	server.Server = httptest.NewServer(server)
+	return server
+}
+
+// Environment returns the endpoints that direct a powerbiapi.Client to this server
+func (server *Server) Environment() powerbiapi.Environment {
+	return powerbiapi.Environment{
+		APIEndpoint:       server.URL,
+		AuthorityHost:     server.URL,
+		ResourceID:        powerbiapi.PublicEnvironment.ResourceID,
+		FabricAPIEndpoint: server.URL,
+	}
+}
+
+// ExpireTokens invalidates all access tokens issued so far, as if they had been revoked
+func (server *Server) ExpireTokens() {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	server.tokens = make(map[string]time.Time)
+}
+
+// ThrottleRequests responds to the next count API requests with 429 Too Many Requests, as the service does when
+// too many requests are made
+func (server *Server) ThrottleRequests(count int) {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	server.throttled = count
+}
+
+// TokensIssued returns the number of access tokens the server has issued
+func (server *Server) TokensIssued() int {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	return server.tokensIssued
+}
+
+// IssueToken returns a new access token accepted by this server, for use with powerbiapi.NewClientWithAccessToken
+func (server *Server) IssueToken() string {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	token, _ := server.newToken()
+	return token
+}
+
+// LastTokenRequest returns the parameters of the most recent request for an access token
+func (server *Server) LastTokenRequest() url.Values {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	return server.tokenRequest
+}
+
+// MSIEndpoint returns the managed identity endpoint of this server, for use with powerbiapi.NewClientWithMSIAuth
+func (server *Server) MSIEndpoint() string {
+	return server.URL + msiPath
+}
+
+// Client returns a powerbiapi.Client authenticated against this server
+func (server *Server) Client() (*powerbiapi.Client, error) {
+	return server.ClientWithEnvironment(server.Environment())
+}
+
+// ClientWithEnvironment returns a powerbiapi.Client authenticated against this server using the given endpoints, such
+// as those from Environment without the Fabric REST API to act like an environment where it is not available
+func (server *Server) ClientWithEnvironment(environment powerbiapi.Environment) (*powerbiapi.Client, error) {
+	return powerbiapi.NewClientWithClientCredentialAuth(environment, testTenantID, testClientID, "secret")
+}
+
+func (server *Server) registerRoutes() {
+	// groups
+	server.handle("POST", `/groups`, server.createGroup)
+	server.handle("GET", `/groups`, server.getGroups)
+	server.handle("DELETE", `/groups/([^/]+)`, server.deleteGroup)
+	server.handle("GET", `/admin/groups`, server.getGroupsAsAdmin)
+	server.handle("PATCH", `/admin/groups/([^/]+)`, server.updateGroupAsAdmin)
+	server.handle("GET", `/groups/([^/]+)/users`, server.getGroupUsers)
+	server.handle("POST", `/groups/([^/]+)/users`, server.addGroupUser)
+	server.handle("PUT", `/groups/([^/]+)/users`, server.updateGroupUser)
+	server.handle("DELETE", `/groups/([^/]+)/users/([^/]+)`, server.deleteUserInGroup)
+	server.handle("POST", `/RefreshUserPermissions`, server.refreshUserPermissions)
+
+	// capacities
+	server.handle("GET", `/capacities`, server.getCapacities)
+	server.handle("POST", `/groups/([^/]+)/AssignToCapacity`, server.groupAssignToCapacity)
+
+	// gateways
+	server.handle("GET", `/gateways`, server.getGateways)
+	server.handle("GET", `/gateways/([^/]+)`, server.getGateway)
+	server.handle("GET", `/gateways/([^/]+)/datasources`, server.getGatewayDatasources)
+	server.handle("POST", `/gateways/([^/]+)/datasources`, server.createGatewayDatasource)
+	server.handle("GET", `/gateways/([^/]+)/datasources/([^/]+)`, server.getGatewayDatasource)
+	server.handle("PATCH", `/gateways/([^/]+)/datasources/([^/]+)`, server.updateGatewayDatasource)
+	server.handle("DELETE", `/gateways/([^/]+)/datasources/([^/]+)`, server.deleteGatewayDatasource)
+	server.handle("GET", `/gateways/([^/]+)/datasources/([^/]+)/users`, server.getGatewayDatasourceUsers)
+	server.handle("POST", `/gateways/([^/]+)/datasources/([^/]+)/users`, server.addGatewayDatasourceUser)
+	server.handle("DELETE", `/gateways/([^/]+)/datasources/([^/]+)/users/([^/]+)`, server.deleteGatewayDatasourceUser)
+
+	// imports
+	server.handle("POST", `/groups/([^/]+)/imports`, server.postImportInGroup)
+	server.handle("GET", `/groups/([^/]+)/imports`, server.getImportsInGroup)
+	server.handle("GET", `/groups/([^/]+)/imports/([^/]+)`, server.getImportInGroup)
+	server.handle("POST", `/groups/([^/]+)/imports/createTemporaryUploadLocation`, server.createTemporaryUploadLocation)
+
+	// datasets
+	server.handle("POST", `/groups/([^/]+)/datasets`, server.postDatasetInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets`, server.getDatasetsInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)`, server.getDatasetInGroup)
+	server.handle("DELETE", `/groups/([^/]+)/datasets/([^/]+)`, server.deleteDatasetInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)/parameters`, server.getParametersInGroup)
+	server.handle("POST", `/groups/([^/]+)/datasets/([^/]+)/Default\.UpdateParameters`, server.updateParametersInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)/datasources`, server.getDatasourcesInGroup)
+	server.handle("POST", `/groups/([^/]+)/datasets/([^/]+)/Default\.UpdateDatasources`, server.updateDatasourcesInGroup)
+	server.handle("POST", `/groups/([^/]+)/datasets/([^/]+)/Default\.TakeOver`, server.takeOverInGroup)
+	server.handle("POST", `/groups/([^/]+)/datasets/([^/]+)/Default\.BindToGateway`, server.bindToGatewayInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)/Default\.DiscoverGateways`, server.discoverGatewaysInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)/refreshSchedule`, server.getRefreshScheduleInGroup)
+	server.handle("PATCH", `/groups/([^/]+)/datasets/([^/]+)/refreshSchedule`, server.updateRefreshScheduleInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)/directQueryRefreshSchedule`, server.getDirectQueryRefreshScheduleInGroup)
+	server.handle("PATCH", `/groups/([^/]+)/datasets/([^/]+)/directQueryRefreshSchedule`, server.updateDirectQueryRefreshScheduleInGroup)
+	server.handle("POST", `/groups/([^/]+)/datasets/([^/]+)/refreshes`, server.refreshDatasetInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)/refreshes`, server.getRefreshHistoryInGroup)
+	server.handle("GET", `/groups/([^/]+)/datasets/([^/]+)/refreshes/([^/]+)`, server.getRefreshExecutionDetailsInGroup)
+	server.handle("DELETE", `/groups/([^/]+)/datasets/([^/]+)/refreshes/([^/]+)`, server.cancelRefreshInGroup)
+	server.handle("GET", `/datasets/([^/]+)/tables`, server.getTables)
+	server.handle("PUT", `/groups/([^/]+)/datasets/([^/]+)/tables/([^/]+)`, server.putTableInGroup)
+	server.handle("POST", `/groups/([^/]+)/datasets/([^/]+)/tables/([^/]+)/rows`, server.postRowsInGroup)
+
+	// reports
+	server.handle("GET", `/groups/([^/]+)/reports`, server.getReportsInGroup)
+	server.handle("GET", `/groups/([^/]+)/reports/([^/]+)`, server.getReportInGroup)
+	server.handle("DELETE", `/groups/([^/]+)/reports/([^/]+)`, server.deleteReportInGroup)
+	server.handle("POST", `/groups/([^/]+)/reports/([^/]+)/Rebind`, server.rebindReportInGroup)
+	server.handle("POST", `/groups/([^/]+)/reports/([^/]+)/Clone`, server.cloneReportInGroup)
+
+	// dataflows
+	server.handle("GET", `/groups/([^/]+)/dataflows`, server.getDataflowsInGroup)
+	server.handle("DELETE", `/groups/([^/]+)/dataflows/([^/]+)`, server.deleteDataflowInGroup)
+
+	// fabric items, for the operations power bi has no api for
+	server.handleFabric("PATCH", `/workspaces/([^/]+)/semanticModels/([^/]+)`, server.updateSemanticModel)
+	server.handleFabric("PATCH", `/workspaces/([^/]+)/reports/([^/]+)`, server.updateFabricReport)
+}
+
+func (server *Server) handle(method string, pattern string, handler func(w http.ResponseWriter, r *http.Request, params []string)) {
+	server.routes = append(server.routes, newRoute(method, pattern, handler))
+}
+
+func (server *Server) handleFabric(method string, pattern string, handler func(w http.ResponseWriter, r *http.Request, params []string)) {
+	server.fabricRoutes = append(server.fabricRoutes, newRoute(method, pattern, handler))
+}
+
+func newRoute(method string, pattern string, handler func(w http.ResponseWriter, r *http.Request, params []string)) route {
+	// Power BI paths are case insensitive
+	return route{
+		method:  method,
+		pattern: regexp.MustCompile("(?i)^" + pattern + "$"),
+		handler: handler,
+	}
+}
+
+// ServeHTTP handles requests to the fake service
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	if strings.HasSuffix(r.URL.Path, "/oauth2/v2.0/token") {
+		server.issueToken(w, r)
+		return
+	}
+
+	if r.URL.Path == msiPath {
+		server.issueMSIToken(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, blobPathPrefix) && r.Method == "PUT" {
+		server.putBlob(w, r)
+		return
+	}
+
+	var routes []route
+	var pathPrefix string
+	switch {
+	case strings.HasPrefix(r.URL.Path, apiPathPrefix):
+		routes, pathPrefix = server.routes, apiPathPrefix
+	case strings.HasPrefix(r.URL.Path, fabricPathPrefix):
+		routes, pathPrefix = server.fabricRoutes, strings.TrimSuffix(fabricPathPrefix, "/")
+	default:
+		writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("No endpoint at '%s'", r.URL.Path))
+		return
+	}
+
+	authorization := r.Header.Get("Authorization")
+	expiry, ok := server.tokens[strings.TrimPrefix(authorization, "Bearer ")]
+	if !strings.HasPrefix(authorization, "Bearer ") || !ok || time.Now().After(expiry) {
+		writeError(w, http.StatusUnauthorized, "TokenExpired", "Access token is missing, invalid or has expired")
+		return
+	}
+
+	if server.throttled > 0 {
+		server.throttled--
+		// the request body is read as the service would before rejecting the request
+		ioutil.ReadAll(r.Body)
+		writeError(w, http.StatusTooManyRequests, "TooManyRequests", "Too many requests")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.EscapedPath(), pathPrefix)
+	for _, route := range routes {
+		matches := route.pattern.FindStringSubmatch(path)
+		if matches == nil || route.method != r.Method {
+			continue
+		}
+
+		params := make([]string, 0, len(matches)-1)
+		for _, match := range matches[1:] {
+			param, err := url.PathUnescape(match)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+				return
+			}
+			params = append(params, param)
+		}
+		route.handler(w, r, params)
+		return
+	}
+
+	writeError(w, http.StatusNotFound, "NotFound", fmt.Sprintf("No endpoint for %s '%s'", r.Method, path))
+}
+
+func (server *Server) issueToken(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, "invalid_request")
+		return
+	}
+	form := r.PostForm
+	server.tokenRequest = form
+
+	switch form.Get("grant_type") {
+	case "password":
+		if form.Get("username") == "" || form.Get("password") == "" {
+			writeTokenError(w, "invalid_grant")
+			return
+		}
+	case "client_credentials":
+		hasAssertion := form.Get("client_assertion_type") == "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" && form.Get("client_assertion") != ""
+		if form.Get("client_secret") == "" && !hasAssertion {
+			writeTokenError(w, "invalid_client")
+			return
+		}
+	default:
+		writeTokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	token, expiresIn := server.newToken()
+	writeJSON(w, map[string]interface{}{
+		"token_type":   "Bearer",
+		"expires_in":   expiresIn,
+		"access_token": token,
+	})
+}
+
+func (server *Server) issueMSIToken(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	server.tokenRequest = query
+	if r.Method != "GET" || r.Header.Get("Metadata") != "true" || query.Get("resource") == "" {
+		writeTokenError(w, "invalid_request")
+		return
+	}
+
+	// unlike Azure Active Directory, the metadata service returns expiry as a string
+	token, expiresIn := server.newToken()
+	writeJSON(w, map[string]interface{}{
+		"token_type":   "Bearer",
+		"expires_in":   fmt.Sprintf("%d", expiresIn),
+		"resource":     query.Get("resource"),
+		"access_token": token,
+	})
+}
+
+func (server *Server) newToken() (string, int) {
+	lifetime := server.TokenLifetime
+	if lifetime == 0 {
+		lifetime = time.Hour
+	}
+
+	token := newID()
+	server.tokens[token] = time.Now().Add(lifetime)
+	server.tokensIssued++
+	return token, int(lifetime.Seconds())
+}
+
+func writeTokenError(w http.ResponseWriter, code string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{"error": code})
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, request interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(request); err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidRequest", err.Error())
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, response interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(response)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, code string, message string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(errorResponse{
+		Error: errorBody{Code: code, Message: message},
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, itemType string, id string) {
+	writeError(w, http.StatusNotFound, "ItemNotFound", fmt.Sprintf("Couldn't find %s '%s'", itemType, id))
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
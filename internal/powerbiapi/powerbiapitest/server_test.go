@@ -0,0 +1,70 @@
+package powerbiapitest
+
+import (
+	"testing"
+
+	"github.com/codecutout/terraform-provider-powerbi/internal/powerbiapi"
+)
+
+func TestServer_getGroupsFilterAndPaging(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client, err := server.Client()
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	for _, name := range []string{"Sales", "Sales - Test", "Finance", "O'Brien"} {
+		if _, err := client.CreateGroup(powerbiapi.CreateGroupRequest{Name: name}); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	testCases := []struct {
+		filter        string
+		top           int
+		skip          int
+		expectedNames []string
+	}{
+		{"", -1, 0, []string{"Sales", "Sales - Test", "Finance", "O'Brien"}},
+		{"name eq 'Finance'", -1, 0, []string{"Finance"}},
+		{"name eq 'O''Brien'", -1, 0, []string{"O'Brien"}},
+		{"startswith(name,'Sales') and name ne 'Sales'", -1, 0, []string{"Sales - Test"}},
+		{"contains(name,'an')", -1, 0, []string{"Finance"}},
+		{"", 2, 1, []string{"Sales - Test", "Finance"}},
+	}
+
+	for _, testCase := range testCases {
+		groups, err := client.GetGroups(testCase.filter, testCase.top, testCase.skip)
+		if err != nil {
+			t.Fatalf("filter '%s': %s", testCase.filter, err)
+		}
+
+		var names []string
+		for _, group := range groups.Value {
+			names = append(names, group.Name)
+		}
+		if len(names) != len(testCase.expectedNames) {
+			t.Fatalf("filter '%s' top %d skip %d: expected %v, found %v", testCase.filter, testCase.top, testCase.skip, testCase.expectedNames, names)
+		}
+		for i := range names {
+			if names[i] != testCase.expectedNames[i] {
+				t.Fatalf("filter '%s' top %d skip %d: expected %v, found %v", testCase.filter, testCase.top, testCase.skip, testCase.expectedNames, names)
+			}
+		}
+	}
+}
+
+func TestServer_unauthorized(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	resp, err := server.Server.Client().Get(server.URL + apiPathPrefix + "/groups")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if resp.StatusCode != 401 {
+		t.Fatalf("expected 401 for request without a token, found %d", resp.StatusCode)
+	}
+}
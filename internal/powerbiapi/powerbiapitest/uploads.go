@@ -0,0 +1,134 @@
+package powerbiapitest
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const blobPathPrefix = "/blob/"
+
+// temporaryUpload is a blob that large files are uploaded to in blocks before being imported
+type temporaryUpload struct {
+	ID        string
+	Signature string
+	Blocks    map[string][]byte
+	Content   []byte
+	Committed bool
+}
+
+// TemporaryUploadLocationsCreated returns the number of temporary upload locations created for large imports
+func (server *Server) TemporaryUploadLocationsCreated() int {
+	server.mux.Lock()
+	defer server.mux.Unlock()
+
+	return len(server.uploads)
+}
+
+func (server *Server) uploadURL(upload *temporaryUpload) string {
+	return fmt.Sprintf("%s%s%s?sv=2019-12-12&sr=b&sp=rcw&sig=%s", server.URL, blobPathPrefix, upload.ID, upload.Signature)
+}
+
+func (server *Server) findUploadByURL(fileURL string) *temporaryUpload {
+	for _, upload := range server.uploads {
+		if server.uploadURL(upload) == fileURL {
+			return upload
+		}
+	}
+	return nil
+}
+
+func (server *Server) createTemporaryUploadLocation(w http.ResponseWriter, r *http.Request, params []string) {
+	if server.findGroupOrNotFound(w, params[0]) == nil {
+		return
+	}
+
+	upload := &temporaryUpload{
+		ID:        newID(),
+		Signature: newID(),
+		Blocks:    make(map[string][]byte),
+	}
+	server.uploads = append(server.uploads, upload)
+
+	writeJSON(w, map[string]interface{}{
+		"url":            server.uploadURL(upload),
+		"expirationTime": time.Now().UTC().Add(time.Hour),
+	})
+}
+
+// putBlob handles the blob storage requests that upload blocks and commit them
+func (server *Server) putBlob(w http.ResponseWriter, r *http.Request) {
+	var upload *temporaryUpload
+	for _, u := range server.uploads {
+		if strings.EqualFold(u.ID, strings.TrimPrefix(r.URL.Path, blobPathPrefix)) {
+			upload = u
+		}
+	}
+
+	// shared access signature URLs are rejected if they also have an authorization header
+	query := r.URL.Query()
+	if upload == nil || query.Get("sig") != upload.Signature || r.Header.Get("Authorization") != "" {
+		writeError(w, http.StatusForbidden, "AuthenticationFailed", "Server failed to authenticate the request")
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "InvalidInput", err.Error())
+		return
+	}
+
+	switch query.Get("comp") {
+	case "block":
+		if query.Get("blockid") == "" {
+			writeError(w, http.StatusBadRequest, "InvalidQueryParameterValue", "blockid is required")
+			return
+		}
+		upload.Blocks[query.Get("blockid")] = data
+	case "blocklist":
+		var blockList struct {
+			Latest []string `xml:"Latest"`
+		}
+		if err := xml.Unmarshal(data, &blockList); err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidXmlDocument", err.Error())
+			return
+		}
+		var content bytes.Buffer
+		for _, blockID := range blockList.Latest {
+			block, ok := upload.Blocks[blockID]
+			if !ok {
+				writeError(w, http.StatusBadRequest, "InvalidBlockList", fmt.Sprintf("Block %s has not been uploaded", blockID))
+				return
+			}
+			content.Write(block)
+		}
+		upload.Content = content.Bytes()
+		upload.Committed = true
+	default:
+		writeError(w, http.StatusBadRequest, "InvalidQueryParameterValue", "comp must be block or blocklist")
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// readImportFileURLContent reads the content of an import from the temporary upload location in the request
+func (server *Server) readImportFileURLContent(r *http.Request) ([]byte, error) {
+	var request struct {
+		FileURL string `json:"fileUrl"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		return nil, err
+	}
+
+	upload := server.findUploadByURL(request.FileURL)
+	if upload == nil || !upload.Committed {
+		return nil, fmt.Errorf("No file has been uploaded to '%s'", request.FileURL)
+	}
+	return upload.Content, nil
+}
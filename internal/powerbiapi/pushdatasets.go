@@ -1,9 +1,6 @@
 package powerbiapi
 
-import (
-	"fmt"
-	"net/url"
-)
+import "net/url"
 
 // PostDatasetInGroupRequest represents the request to create a push dataset
 type PostDatasetInGroupRequest struct {
@@ -92,7 +89,7 @@ func (client *Client) PostDatasetInGroup(groupID string, defaultRetentionPolicy
 		queryParams.Add("defaultRetentionPolicy", defaultRetentionPolicy)
 	}
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets?%s",
+	url := client.buildURL("/groups/%s/datasets?%s",
 		url.PathEscape(groupID),
 		queryParams.Encode())
 
@@ -105,7 +102,7 @@ func (client *Client) PostDatasetInGroup(groupID string, defaultRetentionPolicy
 func (client *Client) GetTables(datasetID string) (*GetTablesResponse, error) {
 
 	var respObj GetTablesResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/datasets/%s/tables", url.PathEscape(datasetID))
+	url := client.buildURL("/datasets/%s/tables", url.PathEscape(datasetID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -114,7 +111,7 @@ func (client *Client) GetTables(datasetID string) (*GetTablesResponse, error) {
 // PutTableInGroup updates the metadata and schema for the specified table, within the specified dataset, from the specified workspace.
 func (client *Client) PutTableInGroup(groupID string, datasetID string, tableName string, request PutTableInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/tables/%s",
+	url := client.buildURL("/groups/%s/datasets/%s/tables/%s",
 		url.PathEscape(groupID),
 		url.PathEscape(datasetID),
 		url.PathEscape(tableName))
@@ -125,7 +122,7 @@ func (client *Client) PutTableInGroup(groupID string, datasetID string, tableNam
 // PostRowsInGroup posts rows into a table in a dataset in a group.
 func (client *Client) PostRowsInGroup(groupID string, datasetID string, tableName string, request PostRowsInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/datasets/%s/tables/%s/rows",
+	url := client.buildURL("/groups/%s/datasets/%s/tables/%s/rows",
 		url.PathEscape(groupID),
 		url.PathEscape(datasetID),
 		url.PathEscape(tableName))
@@ -1,15 +1,34 @@
 package powerbiapi
 
-import (
-	"fmt"
-	"net/url"
-)
+import "net/url"
 
 // RebindReportInGroup represents the request for the RebindReportInGroup API
 type RebindReportInGroupRequest struct {
 	DatasetID string `json:"datasetId"`
 }
 
+// UpdateReportInGroupRequest represents the request to update the properties of a report
+type UpdateReportInGroupRequest struct {
+	DisplayName string `json:"displayName,omitempty"`
+}
+
+// CloneReportInGroupRequest represents the request for the CloneReportInGroup API
+type CloneReportInGroupRequest struct {
+	Name              string `json:"name"`
+	TargetModelID     string `json:"targetModelId,omitempty"`
+	TargetWorkspaceID string `json:"targetWorkspaceId,omitempty"`
+}
+
+// CloneReportInGroupResponse represents the report created by the CloneReportInGroup API
+type CloneReportInGroupResponse struct {
+	ID         string
+	Name       string
+	DatasetID  string
+	ReportType string
+	WebURL     string
+	EmbedURL   string
+}
+
 // GetReportsInGroupResponse represents the details when getting a report in a group.
 type GetReportsInGroupResponse struct {
 	Value []GetReportsInGroupResponseItem
@@ -17,27 +36,29 @@ type GetReportsInGroupResponse struct {
 
 // GetReportsInGroupResponseItem represents a single dataset
 type GetReportsInGroupResponseItem struct {
-	ID        string
-	Name      string
-	DatasetID string
-	WebURL    string
-	EmbedURL  string
+	ID         string
+	Name       string
+	DatasetID  string
+	ReportType string
+	WebURL     string
+	EmbedURL   string
 }
 
 // GetReportsInGroupResponse represents the details when getting a report in a group.
 type GetReportInGroupResponse struct {
-	ID        string
-	Name      string
-	DatasetID string
-	WebURL    string
-	EmbedURL  string
+	ID         string
+	Name       string
+	DatasetID  string
+	ReportType string
+	WebURL     string
+	EmbedURL   string
 }
 
 // GetReportsInGroup returns a list of reports within the specified group.
 func (client *Client) GetReportsInGroup(groupID string) (*GetReportsInGroupResponse, error) {
 
 	var respObj GetReportsInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports", url.PathEscape(groupID))
+	url := client.buildURL("/groups/%s/reports", url.PathEscape(groupID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -47,7 +68,7 @@ func (client *Client) GetReportsInGroup(groupID string) (*GetReportsInGroupRespo
 func (client *Client) GetReportInGroup(groupID string, reportID string) (*GetReportInGroupResponse, error) {
 
 	var respObj GetReportInGroupResponse
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports/%s", url.PathEscape(groupID), url.PathEscape(reportID))
+	url := client.buildURL("/groups/%s/reports/%s", url.PathEscape(groupID), url.PathEscape(reportID))
 	err := client.doJSON("GET", url, nil, &respObj)
 
 	return &respObj, err
@@ -56,7 +77,7 @@ func (client *Client) GetReportInGroup(groupID string, reportID string) (*GetRep
 // DeleteReportInGroup deletes a report that exists within a group.
 func (client *Client) DeleteReportInGroup(groupID string, reportID string) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports/%s", url.PathEscape(groupID), url.PathEscape(reportID))
+	url := client.buildURL("/groups/%s/reports/%s", url.PathEscape(groupID), url.PathEscape(reportID))
 	err := client.doJSON("DELETE", url, nil, nil)
 
 	return err
@@ -65,8 +86,31 @@ func (client *Client) DeleteReportInGroup(groupID string, reportID string) error
 // RebindReportInGroup rebinds the specified report from the specified group to the requested dataset.
 func (client *Client) RebindReportInGroup(groupID string, reportID string, request RebindReportInGroupRequest) error {
 
-	url := fmt.Sprintf("https://api.powerbi.com/v1.0/myorg/groups/%s/reports/%s/Rebind", url.PathEscape(groupID), url.PathEscape(reportID))
+	url := client.buildURL("/groups/%s/reports/%s/Rebind", url.PathEscape(groupID), url.PathEscape(reportID))
 	err := client.doJSON("POST", url, request, nil)
 
 	return err
 }
+
+// CloneReportInGroup clones the specified report from the specified group, optionally into another group and bound to another dataset.
+func (client *Client) CloneReportInGroup(groupID string, reportID string, request CloneReportInGroupRequest) (*CloneReportInGroupResponse, error) {
+
+	var respObj CloneReportInGroupResponse
+	url := client.buildURL("/groups/%s/reports/%s/Clone", url.PathEscape(groupID), url.PathEscape(reportID))
+	err := client.doJSON("POST", url, request, &respObj)
+
+	return &respObj, err
+}
+
+// UpdateReportInGroup updates the properties of a report that exists within a group. Power BI cannot rename
+// reports so this uses the Fabric report API.
+func (client *Client) UpdateReportInGroup(groupID string, reportID string, request UpdateReportInGroupRequest) error {
+
+	url, err := client.buildFabricURL("/workspaces/%s/reports/%s", url.PathEscape(groupID), url.PathEscape(reportID))
+	if err != nil {
+		return err
+	}
+	err = client.doJSON("PATCH", url, &request, nil)
+
+	return err
+}